@@ -0,0 +1,29 @@
+package main
+
+import (
+	"io"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/formater"
+)
+
+// runCat prints feature files with syntax highlighting, as they are. Markdown
+// files and files that do not parse are printed without colors.
+func runCat(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("cat", "[path ...]", stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	return eachInput(fs.Args(), stdin, stderr, func(path, content string) int {
+		if gherkin.IsMarkdownFile(path) {
+			io.WriteString(stdout, content)
+			return exitOK
+		}
+		if err := formater.HighlightANSI(content, stdout); err != nil {
+			io.WriteString(stdout, content)
+			printError(stderr, path, err)
+			return exitError
+		}
+		return exitOK
+	})
+}
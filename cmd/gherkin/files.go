@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/muhqu/go-gherkin"
+)
+
+const stdinName = "<stdin>"
+
+func isFeatureFile(name string) bool {
+	return strings.HasSuffix(name, ".feature") || gherkin.IsMarkdownFile(name)
+}
+
+// collect returns the files named by paths, searching directories for feature
+// files recursively. Hidden directories are skipped.
+func collect(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if p != path && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if isFeatureFile(p) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// eachInput calls fn with the content of each file of paths, or of stdin if
+// there are none. Read errors are reported to stderr. The worst exit code is
+// returned.
+func eachInput(paths []string, stdin io.Reader, stderr io.Writer, fn func(path, content string) int) int {
+	var code exitCode
+	if len(paths) == 0 {
+		data, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", stdinName, err)
+			return exitError
+		}
+		return fn(stdinName, string(data))
+	}
+	files, err := collect(paths)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s\n", err)
+		return exitError
+	}
+	for _, path := range files {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s\n", err)
+			code.set(exitError)
+			continue
+		}
+		code.set(fn(path, string(data)))
+	}
+	return int(code)
+}
+
+// writeFile replaces the content of the existing file at path, keeping its
+// mode.
+func writeFile(path, content string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(content), info.Mode())
+}
+
+// printError reports err about path to stderr. Parse errors come with leading
+// blank lines, which are dropped.
+func printError(stderr io.Writer, path string, err error) {
+	fmt.Fprintf(stderr, "%s: %s\n", path, strings.TrimSpace(err.Error()))
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/formater"
+)
+
+// runFmt formats feature files the way the pretty formater does. Markdown
+// files are left alone.
+func runFmt(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("fmt", "[path ...]", stderr)
+	write := fs.Bool("w", false, "write the result to the file instead of stdout")
+	list := fs.Bool("l", false, "list files whose formatting differs")
+	diff := fs.Bool("d", false, "print diffs instead of the formatted files")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if *write && fs.NArg() == 0 {
+		fmt.Fprintf(stderr, "gherkin fmt: cannot use -w with stdin\n")
+		return exitError
+	}
+
+	gfmt := &formater.GherkinPrettyFormater{}
+	return eachInput(fs.Args(), stdin, stderr, func(path, content string) int {
+		if gherkin.IsMarkdownFile(path) {
+			return exitOK
+		}
+		feature, err := gherkin.ParseFeatureFile(path, content)
+		if err != nil {
+			printError(stderr, path, err)
+			return exitError
+		}
+		var buf bytes.Buffer
+		gfmt.FormatFeature(feature, &buf)
+		formatted := buf.String()
+		changed := formatted != content
+
+		if !*write && !*list && !*diff {
+			io.WriteString(stdout, formatted)
+			return exitOK
+		}
+		if !changed {
+			return exitOK
+		}
+		if *list {
+			fmt.Fprintln(stdout, path)
+		}
+		if *diff {
+			io.WriteString(stdout, unifiedDiff(path, content, formatted))
+		}
+		if *write {
+			if err := writeFile(path, formatted); err != nil {
+				fmt.Fprintf(stderr, "%s\n", err)
+				return exitError
+			}
+			return exitOK
+		}
+		return exitFindings
+	})
+}
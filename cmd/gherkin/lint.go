@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/lint"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+type jsonDiagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Fixable  bool   `json:"fixable,omitempty"`
+}
+
+// runLint reports the diagnostics of the built-in lint rules, as text or as a
+// JSON array. Markdown files are checked without the rules looking at the
+// source text.
+func runLint(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("lint", "[path ...]", stderr)
+	format := fs.String("format", "text", "output format, text or json")
+	configPath := fs.String("config", "", "JSON file configuring the rules")
+	failOn := fs.String("fail-on", "warning", "lowest severity failing the run: info, warning or error")
+	fix := fs.Bool("fix", false, "apply suggested fixes to the files and report what is left")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "gherkin lint: unknown format %q\n", *format)
+		return exitError
+	}
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil {
+		fmt.Fprintf(stderr, "gherkin lint: %s\n", err)
+		return exitError
+	}
+	if *fix && fs.NArg() == 0 {
+		fmt.Fprintf(stderr, "gherkin lint: cannot use -fix with stdin\n")
+		return exitError
+	}
+
+	linter, err := newLinter(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "gherkin lint: %s\n", err)
+		return exitError
+	}
+
+	all := []jsonDiagnostic{}
+	code := eachInput(fs.Args(), stdin, stderr, func(path, content string) int {
+		var diagnostics []*lint.Diagnostic
+		var err error
+		if gherkin.IsMarkdownFile(path) {
+			// without a feature, the rules report the file like LintSource
+			var feature nodes.FeatureNode
+			if feature, err = gherkin.ParseFeatureFile(path, content); err == nil || err == gherkin.ErrNoFeature {
+				diagnostics, err = linter.Lint(feature), nil
+			}
+		} else {
+			if *fix {
+				fixed, err := linter.Fix(content)
+				if err == nil && fixed != content {
+					err = writeFile(path, fixed)
+				}
+				if err != nil {
+					printError(stderr, path, err)
+					return exitError
+				}
+				content = fixed
+			}
+			diagnostics, err = linter.LintSource(content)
+		}
+		if err != nil {
+			printError(stderr, path, err)
+			return exitError
+		}
+
+		code := exitOK
+		for _, d := range diagnostics {
+			if d.Severity >= threshold {
+				code = exitFindings
+			}
+			if *format == "text" {
+				fmt.Fprintf(stdout, "%s:%s\n", path, d)
+			} else {
+				all = append(all, jsonDiagnostic{
+					File:     path,
+					Line:     d.Position.Line,
+					Column:   d.Position.Column,
+					Severity: d.Severity.String(),
+					Rule:     d.Rule,
+					Message:  d.Message,
+					Fixable:  len(d.Edits) > 0,
+				})
+			}
+		}
+		return code
+	})
+	if *format == "json" {
+		if err := writeJSON(stdout, all); err != nil {
+			fmt.Fprintf(stderr, "%s\n", err)
+			return exitError
+		}
+	}
+	return code
+}
+
+func writeJSON(out io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
+
+// newLinter returns a linter with the built-in rules, configured by the file
+// at configPath unless it is empty.
+func newLinter(configPath string) (lint.Linter, error) {
+	linter := lint.NewLinter()
+	if configPath == "" {
+		return linter, nil
+	}
+	config, err := lint.LoadConfig(configPath)
+	if err == nil {
+		err = config.Apply(linter)
+	}
+	return linter, err
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/muhqu/go-gherkin/lsp"
+)
+
+// runLSP serves the Language Server Protocol on stdin and stdout.
+func runLSP(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("lsp", "", stderr)
+	configPath := fs.String("config", "", "JSON file configuring the lint rules")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	linter, err := newLinter(*configPath)
+	if err == nil {
+		err = lsp.NewServer(linter).Serve(stdin, stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gherkin lsp: %s\n", err)
+		return exitError
+	}
+	return exitOK
+}
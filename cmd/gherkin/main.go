@@ -0,0 +1,123 @@
+// Command gherkin formats, lints and inspects Gherkin feature files.
+//
+// Usage:
+//
+//	gherkin <command> [flags] [path ...]
+//
+// The commands are:
+//
+//	fmt      format feature files
+//	cat      print feature files with syntax highlighting
+//	lint     report problems of feature files
+//	parse    print the parsed features as JSON
+//	pickles  print the scenarios as they get executed, outlines expanded
+//	tags     list the tags in use
+//	lsp      serve the Language Server Protocol on stdin and stdout
+//	version  print the version
+//
+// Paths may be files or directories, which are searched for *.feature and
+// *.feature.md files recursively. Without paths, stdin is read.
+//
+// The exit code is 0 on success, 1 if fmt -l/-d found unformatted files or lint
+// found problems, and 2 on usage, read or parse errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/muhqu/go-gherkin"
+)
+
+const (
+	exitOK       = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+type command struct {
+	name  string
+	short string
+	run   func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = []*command{
+	{"fmt", "format feature files", runFmt},
+	{"cat", "print feature files with syntax highlighting", runCat},
+	{"lint", "report problems of feature files", runLint},
+	{"parse", "print the parsed features as JSON", runParse},
+	{"pickles", "print the scenarios as they get executed, outlines expanded", runPickles},
+	{"tags", "list the tags in use", runTags},
+	{"lsp", "serve the Language Server Protocol on stdin and stdout", runLSP},
+	{"version", "print the version", runVersion},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		usage(stdout)
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "gherkin: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitError
+}
+
+func usage(out io.Writer) {
+	fmt.Fprintf(out, "usage: gherkin <command> [flags] [path ...]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-8s %s\n", cmd.name, cmd.short)
+	}
+	fmt.Fprintf(out, "\nRun 'gherkin <command> -h' for the flags of a command.\n")
+}
+
+// newFlagSet returns a flag set for command name, printing its usage to stderr.
+func newFlagSet(name, args string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: gherkin %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func runVersion(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fmt.Fprintf(stdout, "gherkin %s\n", gherkin.VERSION)
+	return exitOK
+}
+
+// ----------------------------------------
+
+// exitCode collects the worst outcome of a command.
+type exitCode int
+
+func (c *exitCode) set(code int) {
+	if code > int(*c) {
+		*c = exitCode(code)
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func runCmd(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, err bytes.Buffer
+	code = run(args, strings.NewReader(stdin), &out, &err)
+	return code, out.String(), err.String()
+}
+
+const messyPath = "testdata/features/sub/messy.feature"
+
+func TestFmt(t *testing.T) {
+	code, out, _ := runCmd("", "fmt", "-l", "testdata/features")
+	assert.Equal(t, exitFindings, code)
+	assert.Equal(t, messyPath+"\n", out)
+
+	code, out, _ = runCmd("", "fmt", "-d", messyPath)
+	assert.Equal(t, exitFindings, code)
+	assert.Contains(t, out, "--- "+messyPath+".orig\n+++ "+messyPath+"\n@@ -1,9 +1,10 @@\n Feature: Messy\n+\n")
+
+	code, out, _ = runCmd("Feature: F\n  Scenario: S\n  Given a step\n", "fmt")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "Feature: F\n\n  Scenario: S\n    Given a step\n", out)
+
+	code, _, errOut := runCmd("", "fmt", "-w")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gherkin fmt: cannot use -w with stdin\n", errOut)
+}
+
+func TestFmtWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gherkin")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	data, _ := ioutil.ReadFile(messyPath)
+	path := filepath.Join(dir, "messy.feature")
+	ioutil.WriteFile(path, data, 0644)
+
+	code, out, _ := runCmd("", "fmt", "-w", "-l", dir)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, path+"\n", out)
+
+	code, out, _ = runCmd("", "fmt", "-l", dir)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "", out)
+}
+
+func TestCat(t *testing.T) {
+	code, out, _ := runCmd("Feature:  F\n", "cat")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "\x1B[1mFeature:\x1B[m  \x1B[29mF\x1B[m\n", out)
+
+	code, out, errOut := runCmd("Scenario: S\n", "cat")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "Scenario: S\n", out)
+	assert.Contains(t, errOut, "<stdin>: parse error")
+}
+
+func TestLint(t *testing.T) {
+	code, out, _ := runCmd("", "lint", "testdata/features")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, messyPath+":2:1: info: missing blank line before scenario (blank-line-before-scenario)\n"+
+		messyPath+":5:3: info: repeated Given step, use And (repeated-step-type)\n", out)
+
+	code, _, _ = runCmd("", "lint", "-fail-on", "info", "testdata/features")
+	assert.Equal(t, exitFindings, code)
+
+	code, out, _ = runCmd("Feature: F\n", "lint", "-format", "json")
+	assert.Equal(t, exitFindings, code)
+	var diagnostics []jsonDiagnostic
+	assert.NoError(t, json.Unmarshal([]byte(out), &diagnostics))
+	assert.Equal(t, []jsonDiagnostic{{
+		File: stdinName, Line: 1, Column: 1, Severity: "warning", Rule: "no-scenarios", Message: "feature has no scenarios",
+	}}, diagnostics)
+
+	code, _, errOut := runCmd("Scenario: without feature\n", "lint")
+	assert.Equal(t, exitError, code)
+	assert.True(t, strings.HasPrefix(errOut, stdinName+": parse error near"), errOut)
+}
+
+func TestParse(t *testing.T) {
+	code, out, _ := runCmd("", "parse", "testdata/features/calc.feature")
+	assert.Equal(t, exitOK, code)
+	var features []*jsonFeature
+	assert.NoError(t, json.Unmarshal([]byte(out), &features))
+	if assert.Len(t, features, 1) {
+		f := features[0]
+		assert.Equal(t, "Calculator", f.Title)
+		assert.Equal(t, []*jsonTag{{"@math", &jsonLocation{1, 1}}}, f.Tags)
+		assert.Equal(t, "Outline", f.Scenarios[1].Type)
+		assert.Equal(t, &jsonStep{Type: "When", Text: "I subtract <b> from <a>", Location: &jsonLocation{12, 5}}, f.Scenarios[1].Steps[1])
+		assert.Equal(t, [][]string{{"a", "b", "c"}, {"5", "3", "2"}, {"9", "1", "8"}}, f.Scenarios[1].Examples[0].Table)
+	}
+}
+
+func TestPickles(t *testing.T) {
+	code, out, _ := runCmd("", "pickles", "testdata/features/calc.feature")
+	assert.Equal(t, exitOK, code)
+	var pickles []*jsonPickle
+	assert.NoError(t, json.Unmarshal([]byte(out), &pickles))
+	if assert.Len(t, pickles, 3) {
+		assert.Equal(t, []string{"@math", "@smoke"}, pickles[0].Tags)
+		assert.Equal(t, &jsonLocation{18, 7}, pickles[2].Location)
+		assert.Equal(t, "I subtract 1 from 9", pickles[2].Steps[1].Text)
+	}
+}
+
+func TestTags(t *testing.T) {
+	code, out, _ := runCmd("", "tags", "testdata/features")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "@math   1\n@smoke  3\n@wip    1\n", out)
+
+	code, out, _ = runCmd("", "tags", "-format", "json", "testdata/features/sub")
+	assert.Equal(t, exitOK, code)
+	var usage []*jsonTagUsage
+	assert.NoError(t, json.Unmarshal([]byte(out), &usage))
+	assert.Equal(t, []*jsonTagUsage{
+		{"@smoke", 2, []string{messyPath + ":2:3", "testdata/features/sub/notes.feature.md:3:2"}},
+		{"@wip", 1, []string{messyPath + ":2:10"}},
+	}, usage)
+}
+
+func TestEmptyFile(t *testing.T) {
+	const empty = "testdata/empty/empty.feature"
+	for _, cmd := range []string{"fmt", "parse", "pickles", "tags"} {
+		for _, path := range []string{empty, empty + ".md"} {
+			if cmd == "fmt" && path != empty {
+				continue
+			}
+			code, _, errOut := runCmd("", cmd, path)
+			assert.Equal(t, exitError, code, cmd+" "+path)
+			assert.Equal(t, path+": file has no feature\n", errOut, cmd+" "+path)
+		}
+	}
+
+	code, out, _ := runCmd("", "lint", "testdata/empty")
+	assert.Equal(t, exitFindings, code)
+	assert.Equal(t, "testdata/empty/empty.feature:1:1: warning: file has no feature (no-scenarios)\n"+
+		"testdata/empty/empty.feature.md:1:1: warning: file has no feature (no-scenarios)\n", out)
+}
+
+func TestUsage(t *testing.T) {
+	code, _, errOut := runCmd("", "frobnicate")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, errOut, `unknown command "frobnicate"`)
+
+	code, _, _ = runCmd("")
+	assert.Equal(t, exitError, code)
+
+	code, _, _ = runCmd("", "tags", "no-such-dir")
+	assert.Equal(t, exitError, code)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+type jsonLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type jsonTag struct {
+	Name     string        `json:"name"`
+	Location *jsonLocation `json:"location,omitempty"`
+}
+
+type jsonFeature struct {
+	URI         string          `json:"uri"`
+	Location    *jsonLocation   `json:"location,omitempty"`
+	Tags        []*jsonTag      `json:"tags,omitempty"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Background  *jsonScenario   `json:"background,omitempty"`
+	Scenarios   []*jsonScenario `json:"scenarios"`
+}
+
+type jsonScenario struct {
+	Type        string          `json:"type"` // Background, Scenario or Outline
+	Location    *jsonLocation   `json:"location,omitempty"`
+	Tags        []*jsonTag      `json:"tags,omitempty"`
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Steps       []*jsonStep     `json:"steps"`
+	Examples    []*jsonExamples `json:"examples,omitempty"`
+}
+
+type jsonStep struct {
+	Type      string        `json:"type"`
+	Text      string        `json:"text"`
+	Location  *jsonLocation `json:"location,omitempty"`
+	Table     [][]string    `json:"table,omitempty"`
+	DocString *string       `json:"docString,omitempty"`
+}
+
+type jsonExamples struct {
+	Title    string        `json:"title"`
+	Location *jsonLocation `json:"location,omitempty"`
+	Table    [][]string    `json:"table,omitempty"`
+}
+
+// runParse prints the features as a JSON array, one element per file.
+func runParse(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("parse", "[path ...]", stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	features := []*jsonFeature{}
+	code := eachInput(fs.Args(), stdin, stderr, func(path, content string) int {
+		feature, err := gherkin.ParseFeatureFile(path, content)
+		if err != nil {
+			printError(stderr, path, err)
+			return exitError
+		}
+		features = append(features, newJSONFeature(path, feature))
+		return exitOK
+	})
+	if err := writeJSON(stdout, features); err != nil {
+		fmt.Fprintf(stderr, "%s\n", err)
+		return exitError
+	}
+	return code
+}
+
+func newJSONLocation(pos events.Position) *jsonLocation {
+	if !pos.IsValid() {
+		return nil
+	}
+	return &jsonLocation{pos.Line, pos.Column}
+}
+
+func newJSONTags(tags []string, positions []events.Position) []*jsonTag {
+	var t []*jsonTag
+	for i, tag := range tags {
+		jt := &jsonTag{Name: "@" + tag}
+		if i < len(positions) {
+			jt.Location = newJSONLocation(positions[i])
+		}
+		t = append(t, jt)
+	}
+	return t
+}
+
+func newJSONFeature(uri string, feature nodes.FeatureNode) *jsonFeature {
+	f := &jsonFeature{
+		URI:         uri,
+		Location:    newJSONLocation(feature.Position()),
+		Tags:        newJSONTags(feature.Tags(), feature.TagPositions()),
+		Title:       feature.Title(),
+		Description: feature.Description(),
+		Scenarios:   []*jsonScenario{},
+	}
+	if feature.Background() != nil {
+		f.Background = newJSONScenario(feature.Background())
+	}
+	for _, scenario := range feature.Scenarios() {
+		f.Scenarios = append(f.Scenarios, newJSONScenario(scenario))
+	}
+	return f
+}
+
+func newJSONScenario(scenario nodes.ScenarioNode) *jsonScenario {
+	s := &jsonScenario{
+		Type:        scenario.NodeType().String(),
+		Location:    newJSONLocation(scenario.Position()),
+		Tags:        newJSONTags(scenario.Tags(), scenario.TagPositions()),
+		Title:       scenario.Title(),
+		Description: scenario.Description(),
+		Steps:       []*jsonStep{},
+	}
+	for _, step := range scenario.Steps() {
+		s.Steps = append(s.Steps, newJSONStep(step.StepType(), step.Text(), step.Position(), step.Table(), step.PyString()))
+	}
+	if outline, ok := scenario.(nodes.OutlineNode); ok {
+		for _, examples := range outline.AllExamples() {
+			e := &jsonExamples{Title: examples.Title(), Location: newJSONLocation(examples.Position())}
+			if examples.Table() != nil {
+				e.Table = examples.Table().Rows()
+			}
+			s.Examples = append(s.Examples, e)
+		}
+	}
+	return s
+}
+
+func newJSONStep(stepType, text string, pos events.Position, table nodes.TableNode, pyString nodes.PyStringNode) *jsonStep {
+	s := &jsonStep{Type: stepType, Text: text, Location: newJSONLocation(pos)}
+	if table != nil {
+		s.Table = table.Rows()
+	}
+	if pyString != nil {
+		docString := pyString.String()
+		s.DocString = &docString
+	}
+	return s
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/pickles"
+)
+
+type jsonPickle struct {
+	URI      string        `json:"uri"`
+	Name     string        `json:"name"`
+	Location *jsonLocation `json:"location,omitempty"` // the examples row for outlines
+	Tags     []string      `json:"tags,omitempty"`
+	Steps    []*jsonStep   `json:"steps"`
+}
+
+// runPickles prints the pickles of the features, as a JSON array or as text.
+func runPickles(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("pickles", "[path ...]", stderr)
+	format := fs.String("format", "json", "output format, json or text")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "gherkin pickles: unknown format %q\n", *format)
+		return exitError
+	}
+
+	all := []*jsonPickle{}
+	code := eachInput(fs.Args(), stdin, stderr, func(path, content string) int {
+		feature, err := gherkin.ParseFeatureFile(path, content)
+		if err != nil {
+			printError(stderr, path, err)
+			return exitError
+		}
+		for _, p := range pickles.Compile(feature) {
+			pos := p.Scenario.Position()
+			if p.Examples != nil {
+				pos = nodes.RowPosition(p.Examples.Table(), p.Row)
+			}
+			if *format == "text" {
+				fmt.Fprintf(stdout, "%s:%d: %s\n", path, pos.Line, p.Name)
+				for _, step := range p.Steps {
+					fmt.Fprintf(stdout, "  %s %s\n", step.Node.StepType(), step.Text)
+				}
+				continue
+			}
+			jp := &jsonPickle{URI: path, Name: p.Name, Location: newJSONLocation(pos), Steps: []*jsonStep{}}
+			for _, tag := range p.Tags {
+				jp.Tags = append(jp.Tags, "@"+tag)
+			}
+			for _, step := range p.Steps {
+				jp.Steps = append(jp.Steps, newJSONStep(step.Node.StepType(), step.Text, step.Node.Position(), step.Table, step.PyString))
+			}
+			all = append(all, jp)
+		}
+		return exitOK
+	})
+	if *format == "json" {
+		if err := writeJSON(stdout, all); err != nil {
+			fmt.Fprintf(stderr, "%s\n", err)
+			return exitError
+		}
+	}
+	return code
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+)
+
+type jsonTagUsage struct {
+	Tag       string   `json:"tag"`
+	Count     int      `json:"count"`
+	Locations []string `json:"locations"` // path:line:column
+}
+
+// runTags lists the tags given on features and scenarios with the number of
+// their uses, sorted by name.
+func runTags(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := newFlagSet("tags", "[path ...]", stderr)
+	format := fs.String("format", "text", "output format, text or json")
+	verbose := fs.Bool("v", false, "list the locations of the tags as well")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(stderr, "gherkin tags: unknown format %q\n", *format)
+		return exitError
+	}
+
+	counts := make(map[string]int)
+	locations := make(map[string][]string)
+	add := func(path string, tags []string, positions []events.Position) {
+		for i, tag := range tags {
+			tag = "@" + tag
+			counts[tag]++
+			if i < len(positions) {
+				locations[tag] = append(locations[tag], fmt.Sprintf("%s:%s", path, positions[i]))
+			}
+		}
+	}
+	code := eachInput(fs.Args(), stdin, stderr, func(path, content string) int {
+		feature, err := gherkin.ParseFeatureFile(path, content)
+		if err != nil {
+			printError(stderr, path, err)
+			return exitError
+		}
+		add(path, feature.Tags(), feature.TagPositions())
+		for _, scenario := range feature.Scenarios() {
+			add(path, scenario.Tags(), scenario.TagPositions())
+		}
+		return exitOK
+	})
+
+	if *format == "json" {
+		usage := []*jsonTagUsage{}
+		for _, tag := range sortedKeys(counts) {
+			usage = append(usage, &jsonTagUsage{tag, counts[tag], locations[tag]})
+		}
+		if err := writeJSON(stdout, usage); err != nil {
+			fmt.Fprintf(stderr, "%s\n", err)
+			return exitError
+		}
+		return code
+	}
+	width := 0
+	for tag := range counts {
+		width = max(width, utf8.RuneCountInString(tag))
+	}
+	for _, tag := range sortedKeys(counts) {
+		fmt.Fprintf(stdout, "%s%s  %d\n", tag, strings.Repeat(" ", width-utf8.RuneCountInString(tag)), counts[tag])
+		if *verbose {
+			for _, location := range locations[tag] {
+				fmt.Fprintf(stdout, "  %s\n", location)
+			}
+		}
+	}
+	return code
+}
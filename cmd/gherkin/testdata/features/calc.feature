@@ -0,0 +1,18 @@
+@math
+Feature: Calculator
+
+  @smoke
+  Scenario: Adding
+    Given a calculator
+    When I add 2 and 2
+    Then the result is 4
+
+  Scenario Outline: Subtracting
+    Given a calculator
+    When I subtract <b> from <a>
+    Then the result is <c>
+
+    Examples:
+      | a | b | c |
+      | 5 | 3 | 2 |
+      | 9 | 1 | 8 |
@@ -0,0 +1,9 @@
+Feature: Messy
+  @smoke @wip
+  Scenario: Typing
+  Given an editor
+  Given a keyboard
+  When I type:
+  """
+  hello
+  """
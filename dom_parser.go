@@ -1,7 +1,10 @@
 package gherkin
 
 import (
-	. "github.com/muhqu/go-gherkin/events"
+	"errors"
+	"strings"
+
+	"github.com/muhqu/go-gherkin/events"
 	. "github.com/muhqu/go-gherkin/nodes"
 )
 
@@ -29,6 +32,9 @@ type gherkinDOMParser struct {
 	pyString       MutablePyStringNode
 	table          MutableTableNode
 	comment        CommentNode
+
+	keywordType           KeywordType // of the last step
+	backgroundKeywordType KeywordType // of the last step of the background
 }
 
 func NewGherkinDOMParser(content string) GherkinDOMParser {
@@ -53,14 +59,62 @@ func (g *gherkinDOMParser) Parse() error {
 	return g.gp.Parse()
 }
 
-func (g *gherkinDOMParser) Execute() {
-	g.gp.Parse()
+func (g *gherkinDOMParser) Execute() error {
+	return g.gp.Parse()
+}
+
+// NewMarkdownGherkinDOMParser is like NewGherkinDOMParser, for Markdown with
+// Gherkin content. See NewMarkdownGherkinParser.
+func NewMarkdownGherkinDOMParser(content string) GherkinDOMParser {
+	g := &gherkinDOMParser{gp: NewMarkdownGherkinParser(content)}
+	g.gp.WithEventProcessor(g)
+	return g
+}
+
+// GherkinDOMBuilder builds the DOM of the events it processes, e.g. of events
+// replayed by ReplayEvents.
+type GherkinDOMBuilder interface {
+	EventProcessor
+	GherkinDOM
+}
+
+func NewGherkinDOMBuilder() GherkinDOMBuilder {
+	return &gherkinDOMParser{processed: true}
 }
 
 func ParseGherkinFeature(content string) (FeatureNode, error) {
 	return NewGherkinDOMParser(content).ParseFeature()
 }
 
+func ParseMarkdownGherkinFeature(content string) (FeatureNode, error) {
+	return NewMarkdownGherkinDOMParser(content).ParseFeature()
+}
+
+// ErrNoFeature is returned by ParseFeatureFile for content without a feature,
+// like an empty file.
+var ErrNoFeature = errors.New("file has no feature")
+
+// IsMarkdownFile reports whether name is that of a Markdown with Gherkin
+// file, ending with ".feature.md".
+func IsMarkdownFile(name string) bool {
+	return strings.HasSuffix(name, ".feature.md")
+}
+
+// ParseFeatureFile parses the content of the file name, as Markdown with
+// Gherkin if IsMarkdownFile says so. Unlike ParseGherkinFeature it never
+// returns a nil feature without error, but ErrNoFeature.
+func ParseFeatureFile(name, content string) (FeatureNode, error) {
+	parse := ParseGherkinFeature
+	if IsMarkdownFile(name) {
+		parse = ParseMarkdownGherkinFeature
+	}
+	feature, err := parse(content)
+	if err == nil && feature == nil {
+		err = ErrNoFeature
+	}
+	return feature, err
+}
+
 func (g *gherkinDOMParser) Feature() FeatureNode {
 	if !g.processed {
 		_, err := g.ParseFeature()
@@ -78,7 +132,10 @@ func (g *gherkinDOMParser) ParseDOM() (GherkinDOM, error) {
 		g.parseErr = err
 		return nil, err
 	}
-	g.gp.Execute()
+	if err := g.gp.Execute(); err != nil {
+		g.parseErr = err
+		return nil, err
+	}
 	return g, nil
 }
 
@@ -95,67 +152,90 @@ func (g *gherkinDOMParser) ProcessEvent(event GherkinEvent) {
 	// default:
 	// 	fmt.Printf("Unexpected Event %T\n", e)
 
-	case *FeatureEvent:
+	case *events.FeatureEvent:
 		g.feature = NewMutableFeatureNode(e.Title, e.Description, e.Tags)
+		g.feature.SetPosition(e.Pos)
+		g.feature.SetTagPositions(e.TagPositions)
 		g.feature.SetComment(g.comment)
 		g.comment = nil
+		g.backgroundKeywordType = UnknownKeywordType
 
-	// case *FeatureEndEvent:
+	// case *events.FeatureEndEvent:
 	// 	// do nothing
 
-	case *BackgroundEvent:
+	case *events.BackgroundEvent:
 		node := NewMutableBackgroundNode(e.Title, e.Tags)
 		node.SetDescription(e.Description)
+		node.SetPosition(e.Pos)
+		node.SetTagPositions(e.TagPositions)
 		g.scenario = node
+		g.keywordType = UnknownKeywordType
 		g.feature.SetBackground(node)
 		node.SetComment(g.comment)
 		g.comment = nil
 
-	case *ScenarioEvent:
+	case *events.ScenarioEvent:
 		node := NewMutableScenarioNode(e.Title, e.Tags)
 		node.SetDescription(e.Description)
+		node.SetPosition(e.Pos)
+		node.SetTagPositions(e.TagPositions)
 		g.scenario = node
+		g.keywordType = g.backgroundKeywordType
 		g.feature.AddScenario(node)
 		node.SetComment(g.comment)
 		g.comment = nil
 
-	case *OutlineEvent:
+	case *events.OutlineEvent:
 		node := NewMutableOutlineNode(e.Title, e.Tags)
 		node.SetDescription(e.Description)
+		node.SetPosition(e.Pos)
+		node.SetTitlePosition(e.TitlePos)
+		node.SetTagPositions(e.TagPositions)
 		g.scenario = node
+		g.keywordType = g.backgroundKeywordType
 		g.outline = node
 		g.feature.AddScenario(node)
 		node.SetComment(g.comment)
 		g.comment = nil
 
-	case *OutlineExamplesEvent:
+	case *events.OutlineExamplesEvent:
 		g.table = nil
 		node := NewMutableOutlineExamplesNode(e.Title)
+		node.SetPosition(e.Pos)
 		g.examples = node
 		node.SetComment(g.comment)
 		g.comment = nil
 
-	case *OutlineExamplesEndEvent:
+	case *events.OutlineExamplesEndEvent:
 		g.examples.SetTable(g.table)
 		g.outline.AddExamples(g.examples)
 		g.examples = nil
 		g.table = nil
 		g.comment = nil
 
-	case *BackgroundEndEvent, *ScenarioEndEvent, *OutlineEndEvent:
+	case *events.BackgroundEndEvent, *events.ScenarioEndEvent, *events.OutlineEndEvent:
+		if _, ok := e.(*events.BackgroundEndEvent); ok {
+			g.backgroundKeywordType = g.keywordType
+		}
 		g.scenario = nil
 		g.outline = nil
 		g.table = nil
 		g.pyString = nil
 		g.comment = nil
 
-	case *StepEvent:
+	case *events.StepEvent:
 		g.step = NewMutableStepNode(e.StepType, e.Text)
+		if kt := StepKeywordType(e.StepType); kt != UnknownKeywordType {
+			g.keywordType = kt
+		}
+		g.step.SetKeywordType(g.keywordType)
+		g.step.SetPosition(e.Pos)
+		g.step.SetTextPosition(e.TextPos)
 		g.scenario.AddStep(g.step)
 		g.step.SetComment(g.comment)
 		g.comment = nil
 
-	case *StepEndEvent:
+	case *events.StepEndEvent:
 		if g.pyString != nil {
 			g.step.WithPyString(g.pyString)
 		} else if g.table != nil {
@@ -166,36 +246,51 @@ func (g *gherkinDOMParser) ProcessEvent(event GherkinEvent) {
 		g.step = nil
 		g.comment = nil
 
-	case *TableEvent:
+	case *events.TableEvent:
 		g.table = NewMutableTableNode()
+		g.table.SetPosition(e.Pos)
 
-	case *TableRowEvent:
+	case *events.TableRowEvent:
 		g.table.NewRow()
+		g.table.SetRowPosition(e.Pos)
 
-	case *TableRowEndEvent:
+	case *events.TableRowEndEvent:
 		g.table.SetRowComment(g.comment)
 		g.comment = nil
 
-	case *TableCellEvent:
+	case *events.TableCellEvent:
 		g.table.AddCell(e.Content)
+		g.table.SetCellPosition(e.Pos)
 
-	// case *TableEndEvent:
+	// case *events.TableEndEvent:
 	// 	// do nothing
 
-	case *PyStringEvent:
+	case *events.PyStringEvent:
 		g.pyStringIndent = len(e.Intent)
 		g.pyString = NewMutablePyStringNode()
+		g.pyString.SetPosition(e.Pos)
+		g.pyString.SetMediaType(e.MediaType)
 
-	case *PyStringLineEvent:
+	case *events.PyStringLineEvent:
 		indent := g.pyStringIndent
+		if indent > len(e.Line) {
+			indent = len(e.Line)
+		}
 		prefix, suffix := e.Line[:indent], e.Line[indent:]
 		line := trimLeadingWS(prefix) + suffix
 		g.pyString.AddLine(line)
+		if pos := e.Pos; pos.IsValid() {
+			// the removed indentation is whitespace, one byte per column
+			removed := len(e.Line) - len(line)
+			pos.Offset += removed
+			pos.Column += removed
+			g.pyString.SetLinePosition(pos)
+		}
 
-		// case *PyStringEndEvent:
+		// case *events.PyStringEndEvent:
 		// 	// do nothing
 
-	case *BlankLineEvent:
+	case *events.BlankLineEvent:
 		node := NewBlankLineNode()
 		node.SetComment(g.comment)
 		if g.scenario != nil {
@@ -203,8 +298,10 @@ func (g *gherkinDOMParser) ProcessEvent(event GherkinEvent) {
 		}
 		g.comment = nil
 
-	case *CommentEvent:
-		g.comment = NewCommentNode(e.Comment)
+	case *events.CommentEvent:
+		comment := NewCommentNode(e.Comment)
+		comment.SetPosition(e.Pos)
+		g.comment = comment
 
 	}
 }
@@ -9,6 +9,24 @@ type EventType int
 
 type Event interface {
 	EventType() EventType
+	Position() Position
+}
+
+// Position of an event, or of the node built from it, within the parsed
+// content. The zero value is used for events that have no position, like the
+// End events.
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number in characters, starting at 1
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
 }
 
 const (
@@ -37,114 +55,159 @@ const (
 )
 
 type FeatureEvent struct {
-	Title       string
-	Description string
-	Tags        []string
+	Title        string
+	Description  string
+	Tags         []string
+	TagPositions []Position
+	Pos          Position
 }
 
 func (*FeatureEvent) EventType() EventType {
 	return FeatureEventType
 }
+func (e *FeatureEvent) Position() Position {
+	return e.Pos
+}
 func (e *FeatureEvent) String() string {
 	return fmt.Sprintf("FeatureEvent(%q,%q,%q)", e.Title, e.Description, e.Tags)
 }
 
 type FeatureEndEvent struct {
+	Pos Position
 }
 
 func (*FeatureEndEvent) EventType() EventType {
 	return FeatureEndEventType
 }
+func (e *FeatureEndEvent) Position() Position {
+	return e.Pos
+}
 func (*FeatureEndEvent) String() string {
 	return "FeatureEndEvent()"
 }
 
 type BackgroundEvent struct {
-	Title       string
-	Description string
-	Tags        []string
+	Title        string
+	Description  string
+	Tags         []string
+	TagPositions []Position
+	Pos          Position
 }
 
 func (*BackgroundEvent) EventType() EventType {
 	return BackgroundEventType
 }
+func (e *BackgroundEvent) Position() Position {
+	return e.Pos
+}
 func (e *BackgroundEvent) String() string {
 	return fmt.Sprintf("BackgroundEvent(%q,%q,%q)", e.Title, e.Description, e.Tags)
 }
 
 type BackgroundEndEvent struct {
+	Pos Position
 }
 
 func (*BackgroundEndEvent) EventType() EventType {
 	return BackgroundEndEventType
 }
+func (e *BackgroundEndEvent) Position() Position {
+	return e.Pos
+}
 func (*BackgroundEndEvent) String() string {
 	return "BackgroundEndEvent()"
 }
 
 type ScenarioEvent struct {
-	Title       string
-	Description string
-	Tags        []string
+	Title        string
+	Description  string
+	Tags         []string
+	TagPositions []Position
+	Pos          Position
 }
 
 func (*ScenarioEvent) EventType() EventType {
 	return ScenarioEventType
 }
+func (e *ScenarioEvent) Position() Position {
+	return e.Pos
+}
 func (e *ScenarioEvent) String() string {
 	return fmt.Sprintf("ScenarioEvent(%q,%q,%q)", e.Title, e.Description, e.Tags)
 }
 
 type ScenarioEndEvent struct {
+	Pos Position
 }
 
 func (*ScenarioEndEvent) EventType() EventType {
 	return ScenarioEndEventType
 }
+func (e *ScenarioEndEvent) Position() Position {
+	return e.Pos
+}
 func (*ScenarioEndEvent) String() string {
 	return "ScenarioEndEvent()"
 }
 
 type OutlineEvent struct {
-	Title       string
-	Description string
-	Tags        []string
+	Title        string
+	Description  string
+	Tags         []string
+	TagPositions []Position
+	Pos          Position
+	TitlePos     Position // of Title, for the placeholders in it
 }
 
 func (e *OutlineEvent) EventType() EventType {
 	return OutlineEventType
 }
+func (e *OutlineEvent) Position() Position {
+	return e.Pos
+}
 func (e *OutlineEvent) String() string {
 	return fmt.Sprintf("OutlineEvent(%q,%q,%q)", e.Title, e.Description, e.Tags)
 }
 
 type OutlineEndEvent struct {
+	Pos Position
 }
 
 func (*OutlineEndEvent) EventType() EventType {
 	return OutlineEndEventType
 }
+func (e *OutlineEndEvent) Position() Position {
+	return e.Pos
+}
 func (*OutlineEndEvent) String() string {
 	return "OutlineEndEvent()"
 }
 
 type OutlineExamplesEvent struct {
 	Title string
+	Pos   Position
 }
 
 func (*OutlineExamplesEvent) EventType() EventType {
 	return OutlineExamplesEventType
 }
+func (e *OutlineExamplesEvent) Position() Position {
+	return e.Pos
+}
 func (*OutlineExamplesEvent) String() string {
 	return "OutlineExamplesEvent()"
 }
 
 type OutlineExamplesEndEvent struct {
+	Pos Position
 }
 
 func (*OutlineExamplesEndEvent) EventType() EventType {
 	return OutlineExamplesEndEventType
 }
+func (e *OutlineExamplesEndEvent) Position() Position {
+	return e.Pos
+}
 func (*OutlineExamplesEndEvent) String() string {
 	return "OutlineExamplesEndEvent()"
 }
@@ -152,125 +215,175 @@ func (*OutlineExamplesEndEvent) String() string {
 type StepEvent struct {
 	StepType string
 	Text     string
+	Pos      Position
+	TextPos  Position // of Text, for the placeholders in it
 }
 
 func (*StepEvent) EventType() EventType {
 	return StepEventType
 }
+func (e *StepEvent) Position() Position {
+	return e.Pos
+}
 func (e *StepEvent) String() string {
 	return fmt.Sprintf("StepEvent(%q,%q)", e.StepType, e.Text)
 }
 
 type StepEndEvent struct {
+	Pos Position
 }
 
 func (*StepEndEvent) EventType() EventType {
 	return StepEndEventType
 }
+func (e *StepEndEvent) Position() Position {
+	return e.Pos
+}
 func (*StepEndEvent) String() string {
 	return "StepEndEvent()"
 }
 
 type PyStringEvent struct {
-	Intent string
+	Intent    string
+	MediaType string // following the opening """, like "json", if any
+	Pos       Position
 }
 
 func (*PyStringEvent) EventType() EventType {
 	return PyStringEventType
 }
+func (e *PyStringEvent) Position() Position {
+	return e.Pos
+}
 func (e *PyStringEvent) String() string {
 	return fmt.Sprintf("PyStringEvent(%q)", e.Intent)
 }
 
 type PyStringLineEvent struct {
 	Line string
+	Pos  Position
 }
 
 func (*PyStringLineEvent) EventType() EventType {
 	return PyStringLineEventType
 }
+func (e *PyStringLineEvent) Position() Position {
+	return e.Pos
+}
 func (e *PyStringLineEvent) String() string {
 	return fmt.Sprintf("PyStringLineEvent(%q)", e.Line)
 }
 
 type PyStringEndEvent struct {
+	Pos Position
 }
 
 func (*PyStringEndEvent) EventType() EventType {
 	return PyStringEndEventType
 }
+func (e *PyStringEndEvent) Position() Position {
+	return e.Pos
+}
 func (*PyStringEndEvent) String() string {
 	return "PyStringEndEvent()"
 }
 
 type TableEvent struct {
+	Pos Position
 }
 
 func (*TableEvent) EventType() EventType {
 	return TableEventType
 }
+func (e *TableEvent) Position() Position {
+	return e.Pos
+}
 func (*TableEvent) String() string {
 	return "TableEvent()"
 }
 
 type TableRowEvent struct {
+	Pos Position
 }
 
 func (*TableRowEvent) EventType() EventType {
 	return TableRowEventType
 }
+func (e *TableRowEvent) Position() Position {
+	return e.Pos
+}
 func (*TableRowEvent) String() string {
 	return "TableRowEvent()"
 }
 
 type TableRowEndEvent struct {
+	Pos Position
 }
 
 func (*TableRowEndEvent) EventType() EventType {
 	return TableRowEndEventType
 }
+func (e *TableRowEndEvent) Position() Position {
+	return e.Pos
+}
 func (*TableRowEndEvent) String() string {
 	return "TableRowEndEvent()"
 }
 
 type TableCellEvent struct {
 	Content string
+	Pos     Position
 }
 
 func (*TableCellEvent) EventType() EventType {
 	return TableCellEventType
 }
+func (e *TableCellEvent) Position() Position {
+	return e.Pos
+}
 func (e *TableCellEvent) String() string {
 	return fmt.Sprintf("TableCellEvent(%q)", e.Content)
 }
 
 type TableEndEvent struct {
+	Pos Position
 }
 
 func (*TableEndEvent) EventType() EventType {
 	return TableEndEventType
 }
+func (e *TableEndEvent) Position() Position {
+	return e.Pos
+}
 func (*TableEndEvent) String() string {
 	return "TableEndEvent()"
 }
 
 type BlankLineEvent struct {
+	Pos Position
 }
 
 func (*BlankLineEvent) EventType() EventType {
 	return BlankLineEventType
 }
+func (e *BlankLineEvent) Position() Position {
+	return e.Pos
+}
 func (*BlankLineEvent) String() string {
 	return "BlankLineEvent()"
 }
 
 type CommentEvent struct {
 	Comment string
+	Pos     Position
 }
 
 func (*CommentEvent) EventType() EventType {
 	return CommentEventType
 }
+func (e *CommentEvent) Position() Position {
+	return e.Pos
+}
 func (e *CommentEvent) String() string {
 	return fmt.Sprintf("CommentEvent(%q)", e.Comment)
 }
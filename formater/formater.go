@@ -266,15 +266,7 @@ func (g *gherkinPrettyPrinter) FormatScenario(node nodes.ScenarioNode) {
 		g.write(fmt.Sprintf("  %s\n", g.colored(c_CYAN, fmtTags(tags))))
 	}
 
-	var scenarioKeyword string
-	switch node.NodeType() {
-	case nodes.BackgroundNodeType:
-		scenarioKeyword = "Background"
-	case nodes.OutlineNodeType:
-		scenarioKeyword = "Scenario Outline"
-	default:
-		scenarioKeyword = "Scenario"
-	}
+	scenarioKeyword := ScenarioKeyword(node)
 
 	if node.Title() != "" {
 		g.linebuff.Writeln(
@@ -413,10 +405,8 @@ func (g *gherkinPrettyPrinter) FormatTable(node nodes.TableNode) {
 		var buf []*styledString
 		buf = append(buf, g.colored(c_WHITE, "      "))
 		for c, str := range row {
-			numstr := strings.Replace(str, "$", "", -1)
-			_, err := strconv.ParseFloat(numstr, 64)
 			var fmtStr string
-			if err != nil {
+			if !IsNumber(str) {
 				fmtStr = fmt.Sprintf(" %%-%ds ", cellwidth[c])
 			} else {
 				fmtStr = fmt.Sprintf(" %%%ds ", cellwidth[c])
@@ -432,7 +422,7 @@ func (g *gherkinPrettyPrinter) FormatTable(node nodes.TableNode) {
 func (g *gherkinPrettyPrinter) FormatPyString(node nodes.PyStringNode) {
 	prefix := "      "
 	quotes := g.colored(c_BOLD, "\"\"\"").String()
-	g.write(prefix + quotes + "\n")
+	g.write(prefix + quotes + node.MediaType() + "\n")
 	g.write(g.colored(c_YELLOW, prefixLines(prefix, node.String())).String())
 	g.write(quotes + "\n")
 }
@@ -446,8 +436,27 @@ func prefixLines(prefix, str string) string {
 }
 
 func fmtTags(tags []string) string {
+	prefixed := make([]string, len(tags))
 	for i, tag := range tags {
-		tags[i] = "@" + tag
+		prefixed[i] = "@" + tag
+	}
+	return strings.Join(prefixed, " ")
+}
+
+// ScenarioKeyword returns the keyword of a scenario, background or outline.
+func ScenarioKeyword(node nodes.ScenarioNode) string {
+	switch node.NodeType() {
+	case nodes.BackgroundNodeType:
+		return "Background"
+	case nodes.OutlineNodeType:
+		return "Scenario Outline"
 	}
-	return strings.Join(tags, " ")
+	return "Scenario"
+}
+
+// IsNumber reports whether a table cell holds a number, which get aligned
+// to the right.
+func IsNumber(str string) bool {
+	_, err := strconv.ParseFloat(strings.Replace(str, "$", "", -1), 64)
+	return err == nil
 }
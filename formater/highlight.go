@@ -0,0 +1,46 @@
+package formater
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/muhqu/go-gherkin"
+)
+
+var tokenStyles = map[gherkin.TokenType]ansiStyle{
+	gherkin.KeywordToken:            c_BOLD,
+	gherkin.TitleToken:              c_WHITE,
+	gherkin.TagToken:                c_CYAN,
+	gherkin.StepKeywordToken:        c_BOLD_GREEN,
+	gherkin.StepTextToken:           c_GREEN,
+	gherkin.ParameterToken:          c_BOLD_CYAN,
+	gherkin.PlaceholderToken:        c_BOLD_MAGENTA,
+	gherkin.TablePipeToken:          c_BOLD_YELLOW,
+	gherkin.TableCellToken:          c_YELLOW,
+	gherkin.DocStringDelimiterToken: c_BOLD,
+	gherkin.DocStringToken:          c_YELLOW,
+	gherkin.CommentToken:            c_GRAY,
+}
+
+// HighlightANSI writes content with ANSI colors, in the colors of the pretty
+// formater, but without reformatting it. Nothing is written if content does
+// not parse.
+func HighlightANSI(content string, out io.Writer) error {
+	tokens, err := gherkin.Tokenize(content)
+	if err != nil {
+		return err
+	}
+	offset := 0
+	for _, t := range tokens {
+		style, ok := tokenStyles[t.Type]
+		if !ok {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "%s\x1B[%sm%s\x1B[m", content[offset:t.Pos.Offset], style, t.Text(content)); err != nil {
+			return err
+		}
+		offset = t.End()
+	}
+	_, err = io.WriteString(out, content[offset:])
+	return err
+}
@@ -0,0 +1,26 @@
+package formater_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/muhqu/go-gherkin/formater"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightANSI(t *testing.T) {
+	content := "@wip\nFeature:   Unformatted  # kept\n  Scenario: S\n     Given \"x\"\n      |a|  b |\n"
+	buf := new(bytes.Buffer)
+	assert.NoError(t, formater.HighlightANSI(content, buf))
+	assert.Equal(t, "\x1B[36m@wip\x1B[m\n\x1B[1mFeature:\x1B[m   \x1B[29mUnformatted\x1B[m  \x1B[30;1m# kept\x1B[m\n", buf.String()[:69])
+	assert.Contains(t, buf.String(), "\x1B[32;1mGiven\x1B[m \x1B[36;1m\"x\"\x1B[m\n")
+	assert.Contains(t, buf.String(), "\x1B[33;1m|\x1B[m\x1B[33ma\x1B[m\x1B[33;1m|\x1B[m  \x1B[33mb\x1B[m \x1B[33;1m|\x1B[m\n")
+
+	// without the escape sequences, it is the original content
+	assert.Equal(t, content, regexp.MustCompile("\x1B\\[[0-9;]*m").ReplaceAllString(buf.String(), ""))
+
+	buf.Reset()
+	assert.Error(t, formater.HighlightANSI("Scenario: without feature\n", buf))
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,376 @@
+package formater
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/reporter"
+)
+
+// GherkinHTMLFormater renders features as a single static HTML page, with an
+// index that can be filtered by tag and collapsible scenarios. Styles and
+// scripts are inlined, so the page needs no external assets.
+//
+// When Results are given, scenarios, steps and examples rows are marked with
+// the status of their last run.
+//
+//	f := &formater.GherkinHTMLFormater{Title: "Calculator", Results: results}
+//	f.FormatFeatures(features, out)
+//
+// Format and FormatFeature write a complete page as well, the other Format
+// methods write HTML fragments.
+type GherkinHTMLFormater struct {
+	Title   string // defaults to "Features"
+	Results []*reporter.FeatureResult
+}
+
+type gherkinHTMLPrinter struct {
+	ghf *GherkinHTMLFormater
+	io.Writer
+
+	results *htmlResults
+	ids     map[nodes.NodeInterface]string
+}
+
+func newGherkinHTMLPrinter(ghf *GherkinHTMLFormater, out io.Writer) *gherkinHTMLPrinter {
+	g := &gherkinHTMLPrinter{}
+	g.ghf = ghf
+	g.Writer = out
+	if ghf.Results != nil {
+		g.results = newHTMLResults(ghf.Results)
+	}
+	g.ids = make(map[nodes.NodeInterface]string)
+	return g
+}
+
+func (ghf *GherkinHTMLFormater) Format(gd gherkin.GherkinDOM, out io.Writer) {
+	ghf.FormatFeature(gd.Feature(), out)
+}
+
+func (ghf *GherkinHTMLFormater) FormatFeatures(features []nodes.FeatureNode, out io.Writer) {
+	g := newGherkinHTMLPrinter(ghf, out)
+	g.FormatPage(features)
+}
+func (ghf *GherkinHTMLFormater) FormatFeature(node nodes.FeatureNode, out io.Writer) {
+	ghf.FormatFeatures([]nodes.FeatureNode{node}, out)
+}
+func (ghf *GherkinHTMLFormater) FormatScenario(node nodes.ScenarioNode, out io.Writer) {
+	g := newGherkinHTMLPrinter(ghf, out)
+	g.FormatScenario(node, nil)
+}
+func (ghf *GherkinHTMLFormater) FormatStep(node nodes.StepNode, out io.Writer) {
+	g := newGherkinHTMLPrinter(ghf, out)
+	g.FormatStep(node)
+}
+func (ghf *GherkinHTMLFormater) FormatTable(node nodes.TableNode, out io.Writer) {
+	g := newGherkinHTMLPrinter(ghf, out)
+	g.FormatTable(node)
+}
+func (ghf *GherkinHTMLFormater) FormatPyString(node nodes.PyStringNode, out io.Writer) {
+	g := newGherkinHTMLPrinter(ghf, out)
+	g.FormatPyString(node)
+}
+
+func (g *gherkinHTMLPrinter) write(format string, args ...interface{}) {
+	fmt.Fprintf(g.Writer, format, args...)
+}
+
+// statusClass returns the CSS class for the status of node, if there are
+// results for it.
+func (g *gherkinHTMLPrinter) statusClass(node nodes.NodeInterface) string {
+	if g.results == nil {
+		return ""
+	}
+	if status, ok := g.results.status[node]; ok {
+		return " " + status.String()
+	}
+	return ""
+}
+
+func (g *gherkinHTMLPrinter) FormatPage(features []nodes.FeatureNode) {
+	title := g.ghf.Title
+	if title == "" {
+		title = "Features"
+	}
+	for i, feature := range features {
+		g.ids[feature] = fmt.Sprintf("feature-%d", i+1)
+		for j, scenario := range feature.Scenarios() {
+			g.ids[scenario] = fmt.Sprintf("feature-%d-scenario-%d", i+1, j+1)
+		}
+	}
+
+	g.write("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	g.write("<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n", html.EscapeString(title), htmlStyle)
+	g.write("<h1>%s</h1>\n", html.EscapeString(title))
+	g.formatIndex(features)
+	for _, feature := range features {
+		g.FormatFeature(feature)
+	}
+	g.write("<script>%s</script>\n</body>\n</html>\n", htmlScript)
+}
+
+func (g *gherkinHTMLPrinter) formatIndex(features []nodes.FeatureNode) {
+	tagSet := make(map[string]bool)
+	for _, feature := range features {
+		for _, scenario := range feature.Scenarios() {
+			for _, tag := range effectiveTags(feature, scenario) {
+				tagSet[tag] = true
+			}
+		}
+	}
+	var tags []string
+	for tag := range tagSet {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	g.write("<nav class=\"index\">\n")
+	if len(tags) > 0 {
+		g.write("<label>Tag <select onchange=\"filterTag(this.value)\">\n<option value=\"\">all</option>\n")
+		for _, tag := range tags {
+			g.write("<option>@%s</option>\n", html.EscapeString(tag))
+		}
+		g.write("</select></label>\n")
+	}
+	g.write("<ul>\n")
+	for _, feature := range features {
+		g.write("<li class=\"feature\"><a href=\"#%s\">%s</a>\n<ul>\n", g.ids[feature], html.EscapeString(feature.Title()))
+		for _, scenario := range feature.Scenarios() {
+			g.write("<li class=\"scenario%s\" data-tags=\"%s\"><a href=\"#%s\">%s</a></li>\n",
+				g.statusClass(scenario), html.EscapeString(fmtTags(effectiveTags(feature, scenario))),
+				g.ids[scenario], html.EscapeString(scenario.Title()))
+		}
+		g.write("</ul>\n</li>\n")
+	}
+	g.write("</ul>\n</nav>\n")
+}
+
+func (g *gherkinHTMLPrinter) FormatFeature(node nodes.FeatureNode) {
+	g.write("<section class=\"feature\" id=\"%s\">\n", g.ids[node])
+	tags := node.Tags()
+	if len(tags) > 0 {
+		g.write("<p class=\"tags\">%s</p>\n", html.EscapeString(fmtTags(tags)))
+	}
+	g.write("<h2><span class=\"keyword\">Feature:</span> %s</h2>\n", html.EscapeString(node.Title()))
+	if node.Description() != "" {
+		g.write("<p class=\"description\">%s</p>\n", html.EscapeString(node.Description()))
+	}
+
+	if node.Background() != nil {
+		g.FormatScenario(node.Background(), node)
+	}
+
+	for _, scenario := range node.Scenarios() {
+		g.FormatScenario(scenario, node)
+	}
+	g.write("</section>\n")
+}
+
+// FormatScenario writes node as a collapsible <details> element. The feature
+// is used for the tags to filter by, and may be nil.
+func (g *gherkinHTMLPrinter) FormatScenario(node nodes.ScenarioNode, feature nodes.FeatureNode) {
+	var attrs string
+	if id, ok := g.ids[node]; ok {
+		attrs += fmt.Sprintf(" id=\"%s\"", id)
+	}
+	if node.NodeType() != nodes.BackgroundNodeType {
+		attrs += fmt.Sprintf(" data-tags=\"%s\"", html.EscapeString(fmtTags(effectiveTags(feature, node))))
+	}
+	class := strings.ToLower(node.NodeType().String())
+	g.write("<details class=\"%s%s\"%s open>\n<summary>", class, g.statusClass(node), attrs)
+	tags := node.Tags()
+	if len(tags) > 0 {
+		g.write("<span class=\"tags\">%s</span> ", html.EscapeString(fmtTags(tags)))
+	}
+	g.write("<span class=\"keyword\">%s:</span> %s</summary>\n", ScenarioKeyword(node), html.EscapeString(node.Title()))
+
+	if node.Description() != "" {
+		g.write("<p class=\"description\">%s</p>\n", html.EscapeString(node.Description()))
+	}
+
+	g.write("<ol class=\"steps\">\n")
+	for _, step := range node.Steps() {
+		g.FormatStep(step)
+	}
+	g.write("</ol>\n")
+
+	if node.NodeType() == nodes.OutlineNodeType {
+		for _, examples := range node.(nodes.OutlineNode).AllExamples() {
+			g.write("<h4><span class=\"keyword\">Examples:</span> %s</h4>\n", html.EscapeString(examples.Title()))
+			if examples.Table() != nil {
+				g.formatTable(examples.Table(), examples)
+			}
+		}
+	}
+	g.write("</details>\n")
+}
+
+func (g *gherkinHTMLPrinter) FormatStep(node nodes.StepNode) {
+	g.write("<li class=\"step%s\"><span class=\"keyword\">%s</span> %s\n",
+		g.statusClass(node), html.EscapeString(node.StepType()), html.EscapeString(node.Text()))
+
+	if node.PyString() != nil {
+		g.FormatPyString(node.PyString())
+	}
+
+	if node.Table() != nil {
+		g.FormatTable(node.Table())
+	}
+
+	if g.results != nil && g.results.errors[node] != "" {
+		g.write("<pre class=\"error\">%s</pre>\n", html.EscapeString(g.results.errors[node]))
+	}
+	g.write("</li>\n")
+}
+
+func (g *gherkinHTMLPrinter) FormatTable(node nodes.TableNode) {
+	g.formatTable(node, nil)
+}
+
+// formatTable writes node as a <table>. For examples, the first row is the
+// header and the other rows are marked with the status of their pickle.
+func (g *gherkinHTMLPrinter) formatTable(node nodes.TableNode, examples nodes.OutlineExamplesNode) {
+	g.write("<table>\n")
+	for i, row := range node.Rows() {
+		cell := "td"
+		var class string
+		if examples != nil {
+			if i == 0 {
+				cell = "th"
+			} else if g.results != nil {
+				if status, ok := g.results.rows[htmlRow{examples, i}]; ok {
+					class = fmt.Sprintf(" class=\"%s\"", status)
+				}
+			}
+		}
+		g.write("<tr%s>", class)
+		for _, str := range row {
+			if cell == "td" && IsNumber(str) {
+				g.write("<td class=\"number\">%s</td>", html.EscapeString(str))
+			} else {
+				g.write("<%s>%s</%s>", cell, html.EscapeString(str), cell)
+			}
+		}
+		g.write("</tr>\n")
+	}
+	g.write("</table>\n")
+}
+
+func (g *gherkinHTMLPrinter) FormatPyString(node nodes.PyStringNode) {
+	var attrs string
+	if mediaType := node.MediaType(); mediaType != "" {
+		attrs = fmt.Sprintf(" data-media-type=\"%s\"", html.EscapeString(mediaType))
+	}
+	g.write("<pre class=\"docstring\"%s>%s</pre>\n", attrs, html.EscapeString(strings.Join(node.Lines(), "\n")))
+}
+
+// effectiveTags returns the tags of feature followed by the ones of scenario.
+func effectiveTags(feature nodes.FeatureNode, scenario nodes.ScenarioNode) []string {
+	var tags []string
+	if feature != nil {
+		tags = append(tags, feature.Tags()...)
+	}
+	return append(tags, scenario.Tags()...)
+}
+
+// ----------------------------------------
+
+// htmlResults indexes the results by the nodes they belong to. Nodes that are
+// run more than once, like background steps or the steps of outlines, get the
+// worst of their statuses.
+type htmlResults struct {
+	status map[nodes.NodeInterface]reporter.Status
+	errors map[nodes.StepNode]string
+	rows   map[htmlRow]reporter.Status
+}
+
+type htmlRow struct {
+	examples nodes.OutlineExamplesNode
+	row      int
+}
+
+func newHTMLResults(features []*reporter.FeatureResult) *htmlResults {
+	r := &htmlResults{
+		status: make(map[nodes.NodeInterface]reporter.Status),
+		errors: make(map[nodes.StepNode]string),
+		rows:   make(map[htmlRow]reporter.Status),
+	}
+	for _, feature := range features {
+		for _, scenario := range feature.Scenarios {
+			pickle := scenario.Pickle
+			r.add(pickle.Scenario, scenario.Status())
+			if pickle.Examples != nil {
+				r.rows[htmlRow{pickle.Examples, pickle.Row}] = scenario.Status()
+			}
+			for _, step := range scenario.Steps {
+				r.add(step.Step.Node, step.Status)
+				if step.Step.Background {
+					r.add(feature.Feature.Background(), step.Status)
+				}
+				if step.Error != "" && r.errors[step.Step.Node] == "" {
+					r.errors[step.Step.Node] = step.Error
+				}
+			}
+		}
+	}
+	return r
+}
+
+func (r *htmlResults) add(node nodes.NodeInterface, status reporter.Status) {
+	if current, ok := r.status[node]; !ok || statusRank(status) > statusRank(current) {
+		r.status[node] = status
+	}
+}
+
+func statusRank(status reporter.Status) int {
+	switch status {
+	case reporter.StatusPassed:
+		return 0
+	case reporter.StatusSkipped:
+		return 1
+	case reporter.StatusFailed:
+		return 5
+	}
+	return int(status) - 1 // pending, undefined, ambiguous
+}
+
+const htmlStyle = `
+body { font-family: sans-serif; margin: 2em; color: #222; }
+.keyword { font-weight: bold; }
+.tags { color: #0a7e8c; }
+.description { white-space: pre-line; color: #555; }
+nav.index ul { list-style: none; padding-left: 1em; }
+details { margin: 0.5em 0; padding: 0.25em 0.75em; border-left: 4px solid #ccc; }
+summary { cursor: pointer; }
+ol.steps { list-style: none; padding-left: 1em; }
+table { border-collapse: collapse; margin: 0.25em 0 0.25em 2em; }
+th, td { border: 1px solid #ccc; padding: 0.1em 0.5em; }
+td.number { text-align: right; }
+pre { margin: 0.25em 0 0.25em 2em; padding: 0.5em; background: #f5f5f5; }
+pre.error { background: #fdecea; color: #a00; }
+.passed { border-color: #2e7d32; background: #edf7ed; }
+.failed { border-color: #c62828; background: #fdecea; }
+.pending, .undefined, .ambiguous { border-color: #f9a825; background: #fff8e1; }
+.skipped { border-color: #9e9e9e; background: #f5f5f5; }
+li.step.passed, li.step.failed, li.step.pending, li.step.undefined, li.step.ambiguous, li.step.skipped { border-left: 4px solid; padding-left: 0.5em; }
+`
+
+const htmlScript = `
+function filterTag(tag) {
+  var features = document.querySelectorAll(".feature");
+  for (var i = 0; i < features.length; i++) {
+    var scenarios = features[i].querySelectorAll("[data-tags]"), shown = 0;
+    for (var j = 0; j < scenarios.length; j++) {
+      var show = !tag || (" " + scenarios[j].getAttribute("data-tags") + " ").indexOf(" " + tag + " ") >= 0;
+      scenarios[j].style.display = show ? "" : "none";
+      if (show) shown++;
+    }
+    features[i].style.display = !tag || shown ? "" : "none";
+  }
+}
+`
@@ -0,0 +1,83 @@
+package formater_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/formater"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/reporter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGherkinHTMLFormater(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature(`@calc
+Feature: Calculator <3>
+
+  Background:
+    Given a calculator
+
+  @wip
+  Scenario Outline: Adding
+     When I add <a> and <b>
+     Then I see <sum>
+
+    Examples: Small numbers
+     | a | b | sum |
+     | 1 | 2 | 3   |
+     | 2 | 2 | 5   |
+
+  Scenario: Notes
+     When I write:
+       """
+       a < b
+       """
+`)
+	assert.NoError(t, err)
+
+	result := reporter.NewFeatureResult("calculator.feature", feature)
+	for _, scenario := range result.Scenarios {
+		for _, step := range scenario.Steps {
+			step.Status = reporter.StatusPassed
+		}
+	}
+	failed := result.Scenarios[1].Steps[2]
+	failed.Status = reporter.StatusFailed
+	failed.Error = "expected 5, got 4"
+
+	var buf bytes.Buffer
+	f := &formater.GherkinHTMLFormater{Title: "Specs", Results: []*reporter.FeatureResult{result}}
+	f.FormatFeatures([]nodes.FeatureNode{feature}, &buf)
+	page := buf.String()
+
+	assert.Contains(t, page, "<title>Specs</title>")
+	assert.Contains(t, page, "<option>@calc</option>\n<option>@wip</option>")
+	assert.Contains(t, page, `<li class="scenario failed" data-tags="@calc @wip"><a href="#feature-1-scenario-1">Adding</a></li>`)
+	assert.Contains(t, page, `<h2><span class="keyword">Feature:</span> Calculator &lt;3&gt;</h2>`)
+	assert.Contains(t, page, `<details class="background passed" open>`)
+	assert.Contains(t, page, `<details class="outline failed" id="feature-1-scenario-1" data-tags="@calc @wip" open>`)
+	assert.Contains(t, page, `<li class="step failed"><span class="keyword">Then</span> I see &lt;sum&gt;`)
+	assert.Contains(t, page, `<pre class="error">expected 5, got 4</pre>`)
+	assert.Contains(t, page, "<tr><th>a</th><th>b</th><th>sum</th></tr>\n"+
+		`<tr class="passed"><td class="number">1</td><td class="number">2</td><td class="number">3</td></tr>`+"\n"+
+		`<tr class="failed"><td class="number">2</td><td class="number">2</td><td class="number">5</td></tr>`)
+	assert.Contains(t, page, `<pre class="docstring">a &lt; b</pre>`)
+	assert.NotContains(t, page, "<link")
+	assert.Equal(t, []string{"calc"}, feature.Tags())
+}
+
+func TestGherkinHTMLFormaterWithoutResults(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature("Feature: F\n  Scenario: S\n    Given a step\n")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	f := &formater.GherkinHTMLFormater{}
+	f.FormatFeature(feature, &buf)
+	page := buf.String()
+
+	assert.Contains(t, page, "<title>Features</title>")
+	assert.Contains(t, page, `<details class="scenario" id="feature-1-scenario-1" data-tags="" open>`)
+	assert.Contains(t, page, `<li class="step"><span class="keyword">Given</span> a step`)
+	assert.NotContains(t, page, "<select")
+}
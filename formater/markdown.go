@@ -0,0 +1,206 @@
+package formater
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+// GherkinMarkdownFormater formats features as GitHub flavored Markdown.
+//
+// Features, scenarios and examples become headings, steps become a list with
+// the step type in bold, tables become Markdown tables and docstrings become
+// fenced code blocks. Tags are rendered as code spans. Comments are skipped.
+type GherkinMarkdownFormater struct {
+	HeadingLevel int // level of the feature heading, defaults to 1
+}
+
+type gherkinMarkdownPrinter struct {
+	gmf *GherkinMarkdownFormater
+	io.Writer
+}
+
+func newGherkinMarkdownPrinter(gmf *GherkinMarkdownFormater, out io.Writer) *gherkinMarkdownPrinter {
+	return &gherkinMarkdownPrinter{gmf, out}
+}
+
+func (gmf *GherkinMarkdownFormater) Format(gd gherkin.GherkinDOM, out io.Writer) {
+	gmf.FormatFeature(gd.Feature(), out)
+}
+
+func (gmf *GherkinMarkdownFormater) FormatFeature(node nodes.FeatureNode, out io.Writer) {
+	g := newGherkinMarkdownPrinter(gmf, out)
+	g.FormatFeature(node)
+}
+func (gmf *GherkinMarkdownFormater) FormatScenario(node nodes.ScenarioNode, out io.Writer) {
+	g := newGherkinMarkdownPrinter(gmf, out)
+	g.FormatScenario(node)
+}
+func (gmf *GherkinMarkdownFormater) FormatStep(node nodes.StepNode, out io.Writer) {
+	g := newGherkinMarkdownPrinter(gmf, out)
+	g.FormatStep(node)
+}
+func (gmf *GherkinMarkdownFormater) FormatTable(node nodes.TableNode, out io.Writer) {
+	g := newGherkinMarkdownPrinter(gmf, out)
+	g.FormatTable(node, "")
+}
+func (gmf *GherkinMarkdownFormater) FormatPyString(node nodes.PyStringNode, out io.Writer) {
+	g := newGherkinMarkdownPrinter(gmf, out)
+	g.FormatPyString(node, "")
+}
+
+func (g *gherkinMarkdownPrinter) write(s string) {
+	io.WriteString(g.Writer, s)
+}
+
+// heading returns the prefix for a heading, depth levels below the feature.
+func (g *gherkinMarkdownPrinter) heading(depth int) string {
+	level := g.gmf.HeadingLevel
+	if level < 1 {
+		level = 1
+	}
+	return strings.Repeat("#", level+depth) + " "
+}
+
+func (g *gherkinMarkdownPrinter) FormatFeature(node nodes.FeatureNode) {
+	g.write(g.heading(0) + "Feature: " + mdEscape(node.Title()) + "\n")
+	if tags := node.Tags(); len(tags) > 0 {
+		g.write("\n" + mdTags(tags) + "\n")
+	}
+	if node.Description() != "" {
+		g.write("\n" + node.Description() + "\n")
+	}
+
+	if node.Background() != nil {
+		g.write("\n")
+		g.FormatScenario(node.Background())
+	}
+
+	for _, scenario := range node.Scenarios() {
+		g.write("\n")
+		g.FormatScenario(scenario)
+	}
+}
+
+func (g *gherkinMarkdownPrinter) FormatScenario(node nodes.ScenarioNode) {
+	title := g.heading(1) + ScenarioKeyword(node)
+	if node.Title() != "" {
+		title += ": " + mdEscape(node.Title())
+	}
+	g.write(title + "\n")
+	if tags := node.Tags(); len(tags) > 0 {
+		g.write("\n" + mdTags(tags) + "\n")
+	}
+	if node.Description() != "" {
+		g.write("\n" + node.Description() + "\n")
+	}
+
+	if len(node.Steps()) > 0 {
+		g.write("\n")
+		for _, step := range node.Steps() {
+			g.FormatStep(step)
+		}
+	}
+
+	if node.NodeType() == nodes.OutlineNodeType {
+		for _, examples := range node.(nodes.OutlineNode).AllExamples() {
+			title := g.heading(2) + "Examples"
+			if examples.Title() != "" {
+				title += ": " + mdEscape(examples.Title())
+			}
+			g.write("\n" + title + "\n")
+			if examples.Table() != nil {
+				g.write("\n")
+				g.FormatTable(examples.Table(), "")
+			}
+		}
+	}
+}
+
+func (g *gherkinMarkdownPrinter) FormatStep(node nodes.StepNode) {
+	g.write(fmt.Sprintf("- **%s** %s\n", node.StepType(), mdEscape(node.Text())))
+
+	// arguments are indented to become part of the list item
+	if node.PyString() != nil {
+		g.write("\n")
+		g.FormatPyString(node.PyString(), "  ")
+	}
+
+	if node.Table() != nil {
+		g.write("\n")
+		g.FormatTable(node.Table(), "  ")
+	}
+}
+
+// FormatTable writes node as a Markdown table, using the first row as the
+// header.
+func (g *gherkinMarkdownPrinter) FormatTable(node nodes.TableNode, indent string) {
+	rows := node.Rows()
+	if len(rows) == 0 {
+		return
+	}
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for c, str := range row {
+			cells[c] = strings.Replace(mdEscape(str), "|", "\\|", -1)
+		}
+		g.write(indent + "| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			align := make([]string, len(row))
+			for c := range row {
+				align[c] = "---"
+				if len(rows) > 1 && c < len(rows[1]) && IsNumber(rows[1][c]) {
+					align[c] = "---:"
+				}
+			}
+			g.write(indent + "| " + strings.Join(align, " | ") + " |\n")
+		}
+	}
+}
+
+// FormatPyString writes node as a fenced code block, with the media type as
+// info string. The fence is made longer than any run of backticks in the
+// content.
+func (g *gherkinMarkdownPrinter) FormatPyString(node nodes.PyStringNode, indent string) {
+	fence := "```"
+	for _, line := range node.Lines() {
+		for strings.Contains(line, fence) {
+			fence += "`"
+		}
+	}
+	g.write(indent + fence + node.MediaType() + "\n")
+	for _, line := range node.Lines() {
+		if line != "" {
+			line = indent + line
+		}
+		g.write(line + "\n")
+	}
+	g.write(indent + fence + "\n")
+}
+
+var mdEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"`", "\\`",
+	"*", "\\*",
+	"_", "\\_",
+	"<", "\\<",
+	"[", "\\[",
+	"]", "\\]",
+)
+
+// mdEscape escapes the characters that would otherwise be taken as inline
+// Markdown or HTML.
+func mdEscape(str string) string {
+	return mdEscaper.Replace(str)
+}
+
+func mdTags(tags []string) string {
+	badges := make([]string, len(tags))
+	for i, tag := range tags {
+		badges[i] = "`@" + tag + "`"
+	}
+	return strings.Join(badges, " ")
+}
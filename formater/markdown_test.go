@@ -0,0 +1,105 @@
+package formater_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/formater"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleGherkinMarkdownFormater() {
+	gp := gherkin.NewGherkinDOMParser(`@calc @smoke
+Feature: Calculator
+  As a user I want *math*.
+
+  Background:
+    Given a calculator
+
+  @wip
+  Scenario Outline: Simple Math
+     When I press "<left> <operator> <right>"
+     Then the result should be "<result>"
+
+    Examples: Operators
+     | left | operator | right | result |
+     | 2    | +        | 2     | 4      |
+     | 6    | *        | 3     | 18     |
+
+  Scenario: Typing
+     When I type:
+       """math
+       2 + 2
+       """
+     And I press the keys:
+       | key |
+       | =   |
+`)
+	f := &formater.GherkinMarkdownFormater{}
+	f.Format(gp, os.Stdout)
+
+	// Output:
+	// # Feature: Calculator
+	//
+	// `@calc` `@smoke`
+	//
+	// As a user I want *math*.
+	//
+	// ## Background
+	//
+	// - **Given** a calculator
+	//
+	// ## Scenario Outline: Simple Math
+	//
+	// `@wip`
+	//
+	// - **When** I press "\<left> \<operator> \<right>"
+	// - **Then** the result should be "\<result>"
+	//
+	// ### Examples: Operators
+	//
+	// | left | operator | right | result |
+	// | ---: | --- | ---: | ---: |
+	// | 2 | + | 2 | 4 |
+	// | 6 | \* | 3 | 18 |
+	//
+	// ## Scenario: Typing
+	//
+	// - **When** I type:
+	//
+	//   ```math
+	//   2 + 2
+	//   ```
+	// - **And** I press the keys:
+	//
+	//   | key |
+	//   | --- |
+	//   | = |
+}
+
+func ExampleGherkinMarkdownFormater_FormatTable() {
+	table := nodes.NewMutableTableNode().WithRows([][]string{
+		{"operator", "meaning"},
+		{"|", "bitwise or"},
+		{"||", "logical or"},
+	})
+	f := &formater.GherkinMarkdownFormater{}
+	f.FormatTable(table, os.Stdout)
+
+	// Output:
+	// | operator | meaning |
+	// | --- | --- |
+	// | \| | bitwise or |
+	// | \|\| | logical or |
+}
+
+func TestMarkdownPyStringLinesUnchanged(t *testing.T) {
+	pyString := nodes.NewMutablePyStringNode().WithLines([]string{"a  ", "", "  b\t"})
+	pyString.SetMediaType("text/plain")
+	var buf bytes.Buffer
+	(&formater.GherkinMarkdownFormater{}).FormatPyString(pyString, &buf)
+	assert.Equal(t, "```text/plain\na  \n\n  b\t\n```\n", buf.String())
+}
@@ -32,7 +32,7 @@ Feature: Dead Simple Calculator
 
   Scenario: Adding 3 numbers
     When I press the following keys:
-      """
+      """text/plain
         2
       + 2
       + 5
@@ -26,7 +26,7 @@ Examples:
 | 3 | + | 4 | 7 |
 Scenario: Adding 3 numbers
 When I press the following keys:
-"""
+""" text/plain
   2
 + 2
 + 5
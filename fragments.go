@@ -0,0 +1,180 @@
+package gherkin
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/muhqu/go-gherkin/events"
+	. "github.com/muhqu/go-gherkin/nodes"
+)
+
+// The fragments are parsed following a prefix making them a feature.
+const (
+	featurePrefix  = "Feature: fragment\n"
+	scenarioPrefix = featurePrefix + "Scenario: fragment\n"
+	stepPrefix     = scenarioPrefix + "Given fragment\n"
+)
+
+func parseFragment(prefix, content string) (FeatureNode, error) {
+	g := &gherkinDOMParser{gp: newFragmentParser(prefix, content)}
+	g.gp.WithEventProcessor(g)
+	return g.ParseFeature()
+}
+
+func fragmentError(pos events.Position, format string, args ...interface{}) error {
+	return &ParseError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// contentPosition returns the position of the byte offset within content.
+func contentPosition(content string, offset int) events.Position {
+	start := strings.LastIndex(content[:offset], "\n") + 1
+	return events.Position{
+		Offset: offset,
+		Line:   strings.Count(content[:offset], "\n") + 1,
+		Column: utf8.RuneCountInString(content[start:offset]) + 1,
+	}
+}
+
+// endPosition returns the position following content, where the parsed node
+// was expected.
+func endPosition(content string) events.Position {
+	return contentPosition(content, len(content))
+}
+
+// unexpectedText returns the error for text of content the parser took as
+// description, which has no position of its own.
+func unexpectedText(content, description string) error {
+	line := description
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	line = trimWS(line)
+	offset := 0
+	for start := 0; start < len(content); {
+		end := strings.IndexByte(content[start:], '\n') + 1
+		if end == 0 {
+			end = len(content) - start
+		}
+		if trimWS(content[start:start+end]) == line {
+			offset = start + skipWS(content[start:start+end], 0)
+			break
+		}
+		start += end
+	}
+	return fragmentError(contentPosition(content, offset), "unexpected %q", line)
+}
+
+// ParseScenario parses a single Scenario, Scenario Outline or Background,
+// with its tags. The positions are relative to content, including those of
+// the *ParseError returned by this and the other fragment parsers.
+func ParseScenario(content string) (ScenarioNode, error) {
+	feature, err := parseFragment(featurePrefix, content)
+	if err != nil {
+		return nil, err
+	}
+	if feature.Description() != "" {
+		return nil, unexpectedText(content, feature.Description())
+	}
+	scenarios := feature.Scenarios()
+	if feature.Background() != nil {
+		scenarios = append([]ScenarioNode{feature.Background()}, scenarios...)
+	}
+	if len(scenarios) != 1 {
+		pos := endPosition(content)
+		if len(scenarios) > 1 {
+			pos = scenarios[1].Position()
+		}
+		return nil, fragmentError(pos, "expected a single scenario, found %d", len(scenarios))
+	}
+	return scenarios[0], nil
+}
+
+// ParseSteps parses a list of steps with their tables and PyStrings. The
+// positions are relative to content.
+func ParseSteps(content string) ([]StepNode, error) {
+	feature, err := parseFragment(scenarioPrefix, content)
+	if err != nil {
+		return nil, err
+	}
+	if extra := extraScenario(feature); extra != nil {
+		return nil, fragmentError(extra.Position(), "expected steps only, found a %s", scenarioName(extra))
+	}
+	scenario := feature.Scenarios()[0]
+	if scenario.Description() != "" {
+		return nil, unexpectedText(content, scenario.Description())
+	}
+	return scenario.Steps(), nil
+}
+
+// extraScenario returns the first scenario or background of content following
+// the scenario of the prefix, nil if there is none.
+func extraScenario(feature FeatureNode) ScenarioNode {
+	var extra ScenarioNode
+	if len(feature.Scenarios()) > 1 {
+		extra = feature.Scenarios()[1]
+	}
+	if background := feature.Background(); background != nil {
+		if extra == nil || background.Position().Offset < extra.Position().Offset {
+			extra = background
+		}
+	}
+	return extra
+}
+
+func scenarioName(scenario ScenarioNode) string {
+	if scenario.NodeType() == BackgroundNodeType {
+		return "background"
+	}
+	return "scenario"
+}
+
+// fragmentStep returns the step the table or PyString of content belongs to.
+func fragmentStep(content string) (StepNode, error) {
+	feature, err := parseFragment(stepPrefix, content)
+	if err != nil {
+		return nil, err
+	}
+	if extra := extraScenario(feature); extra != nil {
+		return nil, fragmentError(extra.Position(), "unexpected %s", scenarioName(extra))
+	}
+	steps := feature.Scenarios()[0].Steps()
+	if len(steps) != 1 {
+		return nil, fragmentError(steps[1].Position(), "unexpected %s step", steps[1].StepType())
+	}
+	return steps[0], nil
+}
+
+// ParseTable parses a table, like one following a step. The positions are
+// relative to content.
+func ParseTable(content string) (TableNode, error) {
+	step, err := fragmentStep(content)
+	if err != nil {
+		return nil, err
+	}
+	if step.Table() == nil {
+		pos := endPosition(content)
+		if step.PyString() != nil {
+			pos = step.PyString().Position()
+		}
+		return nil, fragmentError(pos, "expected a table")
+	}
+	return step.Table(), nil
+}
+
+// ParseDocString parses a PyString, including its """ delimiters, which the
+// lines are indented relative to. The positions are relative to content.
+func ParseDocString(content string) (PyStringNode, error) {
+	step, err := fragmentStep(content)
+	if err != nil {
+		return nil, err
+	}
+	if step.PyString() == nil {
+		pos := endPosition(content)
+		if step.Table() != nil {
+			pos = step.Table().Position()
+		}
+		return nil, fragmentError(pos, "expected a PyString")
+	}
+	return step.PyString(), nil
+}
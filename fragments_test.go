@@ -0,0 +1,111 @@
+package gherkin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleParseTable() {
+	table, _ := gherkin.ParseTable(`
+	| name | balance |
+	| Bob  | $100    |
+	`)
+	fmt.Println(table.Rows())
+	fmt.Println(table.CellPositions()[1][1])
+
+	// Output:
+	// [[name balance] [Bob $100]]
+	// 3:11
+}
+
+func TestParseScenario(t *testing.T) {
+	scenario, err := gherkin.ParseScenario("@wip\nScenario Outline: Adding <a>\n  When I add <a>\n\n  Examples:\n    | a |\n    | 1 |\n")
+	if assert.NoError(t, err) {
+		outline := scenario.(nodes.OutlineNode)
+		assert.Equal(t, "Adding <a>", outline.Title())
+		assert.Equal(t, []string{"wip"}, outline.Tags())
+		assert.Equal(t, events.Position{Offset: 5, Line: 2, Column: 1}, outline.Position())
+		assert.Equal(t, events.Position{Offset: 0, Line: 1, Column: 1}, outline.TagPositions()[0])
+		assert.Equal(t, 7, outline.Examples().Table().RowPositions()[1].Line)
+	}
+
+	background, err := gherkin.ParseScenario("Background:\n  Given a calculator\n")
+	if assert.NoError(t, err) {
+		assert.Equal(t, nodes.BackgroundNodeType, background.NodeType())
+	}
+
+	for content, msg := range map[string]string{
+		"":                                "line 1: expected a single scenario, found 0",
+		"Scenario: A\nScenario: B\n":      "line 2: expected a single scenario, found 2",
+		"Given x\nScenario: A\n":          `line 1: unexpected "Given x"`,
+		"Scenario: A\n  Given x\n  | a\n": `line 3: unexpected "| a"`,
+		"Background: A\n  Given x\nScenario: B\n":   "line 3: expected a single scenario, found 2",
+		"Scenario: A\n  Given x\n  \"\"\"\n  foo\n": "line 5: unexpected end of input",
+	} {
+		_, err := gherkin.ParseScenario(content)
+		assert.EqualError(t, err, msg, content)
+	}
+
+	_, err = gherkin.ParseScenario("# about\n\n  some text\n")
+	if e, ok := err.(*gherkin.ParseError); assert.True(t, ok) {
+		assert.Equal(t, events.Position{Offset: 11, Line: 3, Column: 3}, e.Pos)
+		assert.Equal(t, `unexpected "some text"`, e.Msg)
+	}
+}
+
+func TestParseSteps(t *testing.T) {
+	steps, err := gherkin.ParseSteps("Given a calculator\n  # turned on\nWhen I add:\n  | 1 | 2 |\nThen it shows:\n  \"\"\"\n  3\n  \"\"\"\n")
+	if assert.NoError(t, err) && assert.Len(t, steps, 3) {
+		assert.Equal(t, events.Position{Offset: 0, Line: 1, Column: 1}, steps[0].Position())
+		assert.Equal(t, events.Position{Offset: 33, Line: 3, Column: 1}, steps[1].Position())
+		assert.Equal(t, [][]string{{"1", "2"}}, steps[1].Table().Rows())
+		assert.Equal(t, []string{"3"}, steps[2].PyString().Lines())
+	}
+
+	steps, err = gherkin.ParseSteps("")
+	assert.NoError(t, err)
+	assert.Empty(t, steps)
+
+	_, err = gherkin.ParseSteps("Given x\nScenario: y\n")
+	assert.EqualError(t, err, "line 2: expected steps only, found a scenario")
+	_, err = gherkin.ParseSteps("Given x\nBackground:\n  Given y\n")
+	assert.EqualError(t, err, "line 2: expected steps only, found a background")
+	_, err = gherkin.ParseSteps("some text\nGiven x\n")
+	assert.EqualError(t, err, `line 1: unexpected "some text"`)
+	_, err = gherkin.ParseSteps("Given x\nsome text\n")
+	if e, ok := err.(*gherkin.ParseError); assert.True(t, ok) {
+		assert.Equal(t, 2, e.Pos.Line)
+	}
+}
+
+func TestParseTableAndDocString(t *testing.T) {
+	for content, msg := range map[string]string{
+		"":                                "line 1: expected a table",
+		"| a |\nGiven x\n":                "line 2: unexpected Given step",
+		"\"\"\"\nx\n\"\"\"\n":             "line 1: expected a table",
+		"Scenario: A\n  Given x":          "line 1: unexpected scenario",
+		"| a |\n\n@wip\nScenario: A\n":    "line 4: unexpected scenario",
+		"| a |\nBackground:\n  Given y\n": "line 2: unexpected background",
+	} {
+		_, err := gherkin.ParseTable(content)
+		assert.EqualError(t, err, msg, content)
+		if _, ok := err.(*gherkin.ParseError); !ok {
+			t.Errorf("%q: %T is not a *ParseError", content, err)
+		}
+	}
+
+	doc, err := gherkin.ParseDocString("  \"\"\"\n  {\n    \"a\": 1\n  }\n  \"\"\"\n")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"{", `  "a": 1`, "}"}, doc.Lines())
+		assert.Equal(t, events.Position{Offset: 2, Line: 1, Column: 3}, doc.Position())
+	}
+	_, err = gherkin.ParseDocString("\n  | a |\n")
+	assert.EqualError(t, err, "line 2: expected a PyString")
+	_, err = gherkin.ParseDocString("\"\"\"\nx\n\"\"\"\nScenario: A\n")
+	assert.EqualError(t, err, "line 4: unexpected scenario")
+}
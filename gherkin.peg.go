@@ -732,10 +732,12 @@ func (t *tokens32) Expand(index int) tokenTree {
 type gherkinPeg struct {
 	gherkinPegBase
 
-	buf1    string
-	buf2    string
-	buftags []string
-	bufcmt  string
+	buf1      string
+	buf2      string
+	buftags   []string
+	buftagpos []int
+	bufcmt    string
+	bufpos    int
 
 	Buffer string
 	buffer []rune
@@ -816,6 +818,7 @@ func (p *gherkinPeg) Execute() {
 			begin, end = int(token.begin), int(token.end)
 		case ruleAction0:
 			p.buf1 = buffer[begin:end]
+			p.bufpos = begin
 		case ruleAction1:
 			p.buf2 = buffer[begin:end]
 		case ruleAction2:
@@ -823,12 +826,13 @@ func (p *gherkinPeg) Execute() {
 		case ruleAction3:
 			p.buf2 = p.buf2 + "\n"
 		case ruleAction4:
-			p.beginFeature(trimWS(p.buf1), trimWSML(p.buf2), p.buftags)
-			p.buftags = nil
+			p.beginFeature(trimWS(p.buf1), trimWSML(p.buf2), p.buftags, p.buftagpos, p.bufpos)
+			p.buftags, p.buftagpos = nil, nil
 		case ruleAction5:
 			p.endFeature()
 		case ruleAction6:
 			p.buf1 = buffer[begin:end]
+			p.bufpos = begin
 		case ruleAction7:
 			p.buf2 = buffer[begin:end]
 		case ruleAction8:
@@ -836,12 +840,13 @@ func (p *gherkinPeg) Execute() {
 		case ruleAction9:
 			p.buf2 = p.buf2 + "\n"
 		case ruleAction10:
-			p.beginBackground(trimWS(p.buf1), trimWSML(p.buf2), p.buftags)
-			p.buftags = nil
+			p.beginBackground(trimWS(p.buf1), trimWSML(p.buf2), p.buftags, p.buftagpos, p.bufpos)
+			p.buftags, p.buftagpos = nil, nil
 		case ruleAction11:
 			p.endBackground()
 		case ruleAction12:
 			p.buf1 = buffer[begin:end]
+			p.bufpos = begin
 		case ruleAction13:
 			p.buf2 = buffer[begin:end]
 		case ruleAction14:
@@ -849,12 +854,13 @@ func (p *gherkinPeg) Execute() {
 		case ruleAction15:
 			p.buf2 = p.buf2 + "\n"
 		case ruleAction16:
-			p.beginScenario(trimWS(p.buf1), trimWSML(p.buf2), p.buftags)
-			p.buftags = nil
+			p.beginScenario(trimWS(p.buf1), trimWSML(p.buf2), p.buftags, p.buftagpos, p.bufpos)
+			p.buftags, p.buftagpos = nil, nil
 		case ruleAction17:
 			p.endScenario()
 		case ruleAction18:
 			p.buf1 = buffer[begin:end]
+			p.bufpos = begin
 		case ruleAction19:
 			p.buf2 = buffer[begin:end]
 		case ruleAction20:
@@ -862,30 +868,32 @@ func (p *gherkinPeg) Execute() {
 		case ruleAction21:
 			p.buf2 = p.buf2 + "\n"
 		case ruleAction22:
-			p.beginOutline(trimWS(p.buf1), trimWSML(p.buf2), p.buftags)
-			p.buftags = nil
+			p.beginOutline(trimWS(p.buf1), trimWSML(p.buf2), p.buftags, p.buftagpos, p.bufpos)
+			p.buftags, p.buftagpos = nil, nil
 		case ruleAction23:
 			p.endOutline()
 		case ruleAction24:
 			p.buf1 = buffer[begin:end]
+			p.bufpos = begin
 		case ruleAction25:
-			p.beginOutlineExamples(trimWS(p.buf1))
+			p.beginOutlineExamples(trimWS(p.buf1), p.bufpos)
 		case ruleAction26:
 			p.endOutlineExamples()
 		case ruleAction27:
 			p.buf1 = buffer[begin:end]
+			p.bufpos = begin
 		case ruleAction28:
 			p.buf2 = buffer[begin:end]
 		case ruleAction29:
-			p.beginStep(trimWS(p.buf1), trimWS(p.buf2))
+			p.beginStep(trimWS(p.buf1), trimWS(p.buf2), p.bufpos)
 		case ruleAction30:
 			p.endStep()
 		case ruleAction31:
-			p.beginPyString(buffer[begin:end])
+			p.openPyString(buffer[begin:end], begin)
 		case ruleAction32:
 			p.endPyString()
 		case ruleAction33:
-			p.bufferPyString(buffer[begin:end])
+			p.bufferPyString(buffer[begin:end], begin)
 		case ruleAction34:
 			p.beginTable()
 		case ruleAction35:
@@ -896,12 +904,13 @@ func (p *gherkinPeg) Execute() {
 			p.endTableRow()
 		case ruleAction38:
 			p.beginTableCell()
-			p.endTableCell(trimWS(buffer[begin:end]))
+			p.endTableCell(buffer[begin:end], begin)
 		case ruleAction39:
 			p.buftags = append(p.buftags, buffer[begin:end])
+			p.buftagpos = append(p.buftagpos, begin)
 		case ruleAction40:
 			p.bufcmt = buffer[begin:end]
-			p.triggerComment(p.bufcmt)
+			p.triggerComment(p.bufcmt, begin)
 		case ruleAction41:
 			p.triggerBlankLine()
 
@@ -2278,12 +2287,31 @@ func (p *gherkinPeg) Init() {
 									l169:
 										position, tokenIndex, depth = position169, tokenIndex169, depth169
 									}
+									if !_rules[rulePyStringQuote]() {
+										goto l157
+									}
+								l360:
+									{
+										position361, tokenIndex361, depth361 := position, tokenIndex, depth
+										{
+											position362, tokenIndex362, depth362 := position, tokenIndex, depth
+											if !_rules[ruleNL]() {
+												goto l362
+											}
+											goto l361
+										l362:
+											position, tokenIndex, depth = position362, tokenIndex362, depth362
+										}
+										if !matchDot() {
+											goto l361
+										}
+										goto l360
+									l361:
+										position, tokenIndex, depth = position361, tokenIndex361, depth361
+									}
 									depth--
 									add(rulePegText, position167)
 								}
-								if !_rules[rulePyStringQuote]() {
-									goto l157
-								}
 								if !_rules[ruleNL]() {
 									goto l157
 								}
@@ -2405,7 +2433,7 @@ func (p *gherkinPeg) Init() {
 		},
 		/* 9 StepArgument <- <(Table / PyString)> */
 		nil,
-		/* 10 PyString <- <((WS* NL)* <WS*> PyStringQuote NL Action31 (!(WS* PyStringQuote) PyStringLine)* WS* PyStringQuote LineEnd Action32)> */
+		/* 10 PyString <- <((WS* NL)* <(WS* PyStringQuote (!NL .)*)> NL Action31 (!(WS* PyStringQuote) PyStringLine)* WS* PyStringQuote LineEnd Action32)> */
 		nil,
 		/* 11 PyStringQuote <- <('"' '"' '"')> */
 		func() bool {
@@ -3777,7 +3805,7 @@ func (p *gherkinPeg) Init() {
 			return false
 		},
 		nil,
-		/* 31 Action0 <- <{ p.buf1 = buffer[begin:end] }> */
+		/* 31 Action0 <- <{ p.buf1 = buffer[begin:end]; p.bufpos = begin }> */
 		nil,
 		/* 32 Action1 <- <{ p.buf2 = buffer[begin:end] }> */
 		nil,
@@ -3785,11 +3813,11 @@ func (p *gherkinPeg) Init() {
 		nil,
 		/* 34 Action3 <- <{ p.buf2 = p.buf2 + "\n" }> */
 		nil,
-		/* 35 Action4 <- <{ p.beginFeature(trimWS(p.buf1), trimWSML(p.buf2), p.buftags); p.buftags = nil }> */
+		/* 35 Action4 <- <{ p.beginFeature(trimWS(p.buf1), trimWSML(p.buf2), p.buftags, p.buftagpos, p.bufpos); p.buftags, p.buftagpos = nil, nil }> */
 		nil,
 		/* 36 Action5 <- <{ p.endFeature() }> */
 		nil,
-		/* 37 Action6 <- <{ p.buf1 = buffer[begin:end] }> */
+		/* 37 Action6 <- <{ p.buf1 = buffer[begin:end]; p.bufpos = begin }> */
 		nil,
 		/* 38 Action7 <- <{ p.buf2 = buffer[begin:end] }> */
 		nil,
@@ -3797,11 +3825,11 @@ func (p *gherkinPeg) Init() {
 		nil,
 		/* 40 Action9 <- <{ p.buf2 = p.buf2 + "\n" }> */
 		nil,
-		/* 41 Action10 <- <{ p.beginBackground(trimWS(p.buf1), trimWSML(p.buf2), p.buftags); p.buftags = nil }> */
+		/* 41 Action10 <- <{ p.beginBackground(trimWS(p.buf1), trimWSML(p.buf2), p.buftags, p.buftagpos, p.bufpos); p.buftags, p.buftagpos = nil, nil }> */
 		nil,
 		/* 42 Action11 <- <{ p.endBackground() }> */
 		nil,
-		/* 43 Action12 <- <{ p.buf1 = buffer[begin:end] }> */
+		/* 43 Action12 <- <{ p.buf1 = buffer[begin:end]; p.bufpos = begin }> */
 		nil,
 		/* 44 Action13 <- <{ p.buf2 = buffer[begin:end] }> */
 		nil,
@@ -3809,11 +3837,11 @@ func (p *gherkinPeg) Init() {
 		nil,
 		/* 46 Action15 <- <{ p.buf2 = p.buf2 + "\n" }> */
 		nil,
-		/* 47 Action16 <- <{ p.beginScenario(trimWS(p.buf1), trimWSML(p.buf2), p.buftags); p.buftags = nil }> */
+		/* 47 Action16 <- <{ p.beginScenario(trimWS(p.buf1), trimWSML(p.buf2), p.buftags, p.buftagpos, p.bufpos); p.buftags, p.buftagpos = nil, nil }> */
 		nil,
 		/* 48 Action17 <- <{ p.endScenario() }> */
 		nil,
-		/* 49 Action18 <- <{ p.buf1 = buffer[begin:end] }> */
+		/* 49 Action18 <- <{ p.buf1 = buffer[begin:end]; p.bufpos = begin }> */
 		nil,
 		/* 50 Action19 <- <{ p.buf2 = buffer[begin:end] }> */
 		nil,
@@ -3821,29 +3849,29 @@ func (p *gherkinPeg) Init() {
 		nil,
 		/* 52 Action21 <- <{ p.buf2 = p.buf2 + "\n" }> */
 		nil,
-		/* 53 Action22 <- <{ p.beginOutline(trimWS(p.buf1), trimWSML(p.buf2), p.buftags); p.buftags = nil }> */
+		/* 53 Action22 <- <{ p.beginOutline(trimWS(p.buf1), trimWSML(p.buf2), p.buftags, p.buftagpos, p.bufpos); p.buftags, p.buftagpos = nil, nil }> */
 		nil,
 		/* 54 Action23 <- <{ p.endOutline() }> */
 		nil,
-		/* 55 Action24 <- <{ p.buf1 = buffer[begin:end] }> */
+		/* 55 Action24 <- <{ p.buf1 = buffer[begin:end]; p.bufpos = begin }> */
 		nil,
-		/* 56 Action25 <- <{ p.beginOutlineExamples(trimWS(p.buf1)) }> */
+		/* 56 Action25 <- <{ p.beginOutlineExamples(trimWS(p.buf1), p.bufpos) }> */
 		nil,
 		/* 57 Action26 <- <{ p.endOutlineExamples() }> */
 		nil,
-		/* 58 Action27 <- <{ p.buf1 = buffer[begin:end] }> */
+		/* 58 Action27 <- <{ p.buf1 = buffer[begin:end]; p.bufpos = begin }> */
 		nil,
 		/* 59 Action28 <- <{ p.buf2 = buffer[begin:end] }> */
 		nil,
-		/* 60 Action29 <- <{ p.beginStep(trimWS(p.buf1), trimWS(p.buf2)) }> */
+		/* 60 Action29 <- <{ p.beginStep(trimWS(p.buf1), trimWS(p.buf2), p.bufpos) }> */
 		nil,
 		/* 61 Action30 <- <{ p.endStep() }> */
 		nil,
-		/* 62 Action31 <- <{ p.beginPyString(buffer[begin:end]) }> */
+		/* 62 Action31 <- <{ p.openPyString(buffer[begin:end], begin) }> */
 		nil,
 		/* 63 Action32 <- <{ p.endPyString() }> */
 		nil,
-		/* 64 Action33 <- <{ p.bufferPyString(buffer[begin:end]) }> */
+		/* 64 Action33 <- <{ p.bufferPyString(buffer[begin:end], begin) }> */
 		nil,
 		/* 65 Action34 <- <{ p.beginTable() }> */
 		nil,
@@ -3853,11 +3881,11 @@ func (p *gherkinPeg) Init() {
 		nil,
 		/* 68 Action37 <- <{ p.endTableRow() }> */
 		nil,
-		/* 69 Action38 <- <{ p.beginTableCell(); p.endTableCell(trimWS(buffer[begin:end])) }> */
+		/* 69 Action38 <- <{ p.beginTableCell(); p.endTableCell(buffer[begin:end], begin) }> */
 		nil,
-		/* 70 Action39 <- <{ p.buftags = append(p.buftags, buffer[begin:end]) }> */
+		/* 70 Action39 <- <{ p.buftags = append(p.buftags, buffer[begin:end]); p.buftagpos = append(p.buftagpos, begin) }> */
 		nil,
-		/* 71 Action40 <- <{ p.bufcmt = buffer[begin:end]; p.triggerComment(p.bufcmt) }> */
+		/* 71 Action40 <- <{ p.bufcmt = buffer[begin:end]; p.triggerComment(p.bufcmt, begin) }> */
 		nil,
 		/* 72 Action41 <- <{ p.triggerBlankLine() }> */
 		nil,
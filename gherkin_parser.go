@@ -1,7 +1,10 @@
 package gherkin
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/muhqu/go-gherkin/events"
 )
@@ -11,7 +14,10 @@ type GherkinParser interface {
 	WithEventProcessor(EventProcessor)
 	Init()
 	Parse() error
-	Execute()
+	// Execute emits the events of the content parsed. It returns the
+	// *EventError of the first EventErrorProcessor that failed, after which no
+	// more events are emitted.
+	Execute() error
 }
 
 func NewGherkinParser(content string) GherkinParser {
@@ -23,7 +29,16 @@ func NewGherkinParser(content string) GherkinParser {
 }
 
 type gherkinPegWrapper struct {
-	gp *gherkinPeg
+	gp     *gherkinPeg
+	prefix string // of a fragment, see newFragmentParser
+}
+
+// newFragmentParser returns a parser of prefix followed by content, which
+// reports the positions relative to content. prefix must end with "\n".
+func newFragmentParser(prefix, content string) *gherkinPegWrapper {
+	gpw := NewGherkinParser(prefix + content).(*gherkinPegWrapper)
+	gpw.prefix = prefix
+	return gpw
 }
 
 func (gpw *gherkinPegWrapper) WithLogFn(logFn LogFn) {
@@ -36,14 +51,69 @@ func (gpw *gherkinPegWrapper) WithEventProcessor(ep EventProcessor) {
 
 func (gpw *gherkinPegWrapper) Init() {
 	gpw.gp.Init()
+	gpw.gp.err = nil
+	gpw.gp.lines = newLineIndex(gpw.gp.buffer)
+	gpw.gp.lines.prefixLines = strings.Count(gpw.prefix, "\n")
+	gpw.gp.lines.prefixBytes = len(gpw.prefix)
 }
 
 func (gpw *gherkinPegWrapper) Parse() error {
-	return gpw.gp.Parse()
+	err := gpw.gp.Parse()
+	if err == nil {
+		return nil
+	}
+	end := 0
+	for _, token := range gpw.gp.tokenTree.Error() {
+		if int(token.end) > end {
+			end = int(token.end)
+		}
+	}
+	msg := "unexpected end of input"
+	if runes := gpw.gp.buffer; end < len(runes) && runes[end] != end_symbol && trimWS(string(runes[end:])) != "" {
+		line := string(runes[end:])
+		if i := strings.IndexRune(line, '\n'); i >= 0 {
+			line = line[:i]
+		}
+		msg = fmt.Sprintf("unexpected %q", trimWS(line))
+	}
+	e := &ParseError{Pos: gpw.gp.lines.position(end), Msg: msg, err: err}
+	if gpw.prefix == "" {
+		// the report of the peg parser would refer to the lines of the prefix
+		e.report = err.Error()
+	}
+	return e
 }
 
-func (gpw *gherkinPegWrapper) Execute() {
+func (gpw *gherkinPegWrapper) Execute() error {
 	gpw.gp.Execute()
+	return gpw.gp.err
+}
+
+// ParseError is returned by Parse. Pos tells where parsing got stuck, which is
+// the furthest any rule got and usually on the offending line. Msg is a short
+// description for editors, Error returns the full report.
+type ParseError struct {
+	Pos    events.Position
+	Msg    string
+	err    error  // of the PEG parser, nil for the other parsers
+	report string // of the PEG parser, "line N: Msg" if empty
+}
+
+func (e *ParseError) Error() string {
+	if e.report != "" {
+		return e.report
+	}
+	return fmt.Sprintf("line %d: %s", e.Pos.Line, e.Msg)
+}
+
+func (e *ParseError) Position() events.Position {
+	return e.Pos
+}
+
+// Unwrap returns the error of the PEG parser, nil if the error comes from the
+// Markdown or stream parser.
+func (e *ParseError) Unwrap() error {
+	return e.err
 }
 
 // ----------------------------------------
@@ -58,6 +128,63 @@ func (fn EventProcessorFn) ProcessEvent(e GherkinEvent) {
 	fn(e)
 }
 
+// EventErrorProcessor is an EventProcessor that may fail, e.g. on a forbidden
+// tag. The parsers call ProcessEventError instead of ProcessEvent, and stop at
+// the first error, returning it as *EventError.
+type EventErrorProcessor interface {
+	EventProcessor
+	ProcessEventError(GherkinEvent) error
+}
+
+type EventErrorProcessorFn func(GherkinEvent) error
+
+// ProcessEvent ignores the error, for when fn is used as plain EventProcessor.
+func (fn EventErrorProcessorFn) ProcessEvent(e GherkinEvent) {
+	fn(e)
+}
+
+func (fn EventErrorProcessorFn) ProcessEventError(e GherkinEvent) error {
+	return fn(e)
+}
+
+// EventError is returned by the parsers when an EventErrorProcessor failed.
+// Pos is that of the Event it failed on, which is the zero Position for the
+// End events.
+type EventError struct {
+	Pos   events.Position
+	Event GherkinEvent
+	Err   error
+}
+
+func (e *EventError) Error() string {
+	if !e.Pos.IsValid() {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("line %d: %s", e.Pos.Line, e.Err)
+}
+
+func (e *EventError) Position() events.Position {
+	return e.Pos
+}
+
+// processEvent passes e to the processors, up to the first one that fails.
+func processEvent(eps []EventProcessor, e GherkinEvent) error {
+	for _, ep := range eps {
+		if err := processEventError(ep, e); err != nil {
+			return &EventError{Pos: e.Position(), Event: e, Err: err}
+		}
+	}
+	return nil
+}
+
+func processEventError(ep EventProcessor, e GherkinEvent) error {
+	if eep, ok := ep.(EventErrorProcessor); ok {
+		return eep.ProcessEventError(e)
+	}
+	ep.ProcessEvent(e)
+	return nil
+}
+
 type GherkinEvent events.Event
 
 // ----------------------------------------
@@ -65,6 +192,76 @@ type GherkinEvent events.Event
 type gherkinPegBase struct {
 	logFn           LogFn
 	eventProcessors []EventProcessor
+	lines           *lineIndex
+	err             error // of the first EventErrorProcessor that failed
+
+	pendingTable    bool
+	pendingTableRow bool
+}
+
+// ----------------------------------------
+
+// lineIndex translates the rune offsets of the peg parser into positions.
+type lineIndex struct {
+	runes      []rune
+	lineStarts []int // rune offset of each line
+	lineBytes  []int // byte offset of each line
+
+	// lines and bytes of a prefix the positions are relative to
+	prefixLines, prefixBytes int
+}
+
+func newLineIndex(runes []rune) *lineIndex {
+	l := &lineIndex{runes: runes, lineStarts: []int{0}, lineBytes: []int{0}}
+	b := 0
+	for i, r := range runes {
+		b += utf8.RuneLen(r)
+		if r == '\n' {
+			l.lineStarts = append(l.lineStarts, i+1)
+			l.lineBytes = append(l.lineBytes, b)
+		}
+	}
+	return l
+}
+
+func (l *lineIndex) position(offset int) events.Position {
+	if l == nil || offset < 0 || offset > len(l.runes) {
+		return events.Position{}
+	}
+	line := sort.Search(len(l.lineStarts), func(i int) bool { return l.lineStarts[i] > offset }) - 1
+	start, b := l.lineStarts[line], l.lineBytes[line]
+	for _, r := range l.runes[start:offset] {
+		b += utf8.RuneLen(r)
+	}
+	return events.Position{Offset: b - l.prefixBytes, Line: line + 1 - l.prefixLines, Column: offset - start + 1}
+}
+
+// keywordPosition returns the position of keyword, given the offset of the
+// title that follows it.
+func (l *lineIndex) keywordPosition(titleOffset int, keyword string) events.Position {
+	if l == nil {
+		return events.Position{}
+	}
+	offset := titleOffset
+	for offset > 0 && isWS(l.runes[offset-1]) {
+		offset--
+	}
+	return l.position(offset - utf8.RuneCountInString(keyword))
+}
+
+// skipWS returns the offset of the first non-whitespace rune at or after offset.
+func (l *lineIndex) skipWS(offset int) int {
+	if l == nil {
+		return offset
+	}
+	for offset < len(l.runes) && isWS(l.runes[offset]) {
+		offset++
+	}
+	return offset
+}
+
+func isWS(r rune) bool {
+	return r == ' ' || r == '\t'
 }
 
 // ----------------------------------------
@@ -78,73 +275,103 @@ func (gp *gherkinPegBase) log(msg string, args ...interface{}) {
 }
 
 func (gp *gherkinPegBase) emit(e GherkinEvent) {
-	for _, ep := range gp.eventProcessors {
-		ep.ProcessEvent(e)
+	if gp.err == nil {
+		gp.err = processEvent(gp.eventProcessors, e)
+	}
+}
+
+// tagPositions returns the positions of the '@' of the tags, given the
+// offsets of their names.
+func (gp *gherkinPegBase) tagPositions(offsets []int) []events.Position {
+	if offsets == nil {
+		return nil
 	}
+	positions := make([]events.Position, len(offsets))
+	for i, offset := range offsets {
+		positions[i] = gp.lines.position(offset - 1)
+	}
+	return positions
 }
 
-func (gp *gherkinPegBase) beginFeature(title, description string, tags []string) {
+func (gp *gherkinPegBase) beginFeature(title, description string, tags []string, tagOffsets []int, titleOffset int) {
 	gp.log("BeginFeature: %#v: %#v tags:%+v", title, description, tags)
-	gp.emit(&events.FeatureEvent{title, description, tags})
+	pos := gp.lines.keywordPosition(titleOffset, "Feature:")
+	gp.emit(&events.FeatureEvent{Title: title, Description: description, Tags: tags, TagPositions: gp.tagPositions(tagOffsets), Pos: pos})
 }
 func (gp *gherkinPegBase) endFeature() {
 	gp.log("EndFeature")
 	gp.emit(&events.FeatureEndEvent{})
 }
 
-func (gp *gherkinPegBase) beginBackground(title, description string, tags []string) {
+func (gp *gherkinPegBase) beginBackground(title, description string, tags []string, tagOffsets []int, titleOffset int) {
 	gp.log("BeginBackground: %#v: %#v tags:%+v", title, description, tags)
-	gp.emit(&events.BackgroundEvent{title, description, tags})
+	pos := gp.lines.keywordPosition(titleOffset, "Background:")
+	gp.emit(&events.BackgroundEvent{Title: title, Description: description, Tags: tags, TagPositions: gp.tagPositions(tagOffsets), Pos: pos})
 }
 func (gp *gherkinPegBase) endBackground() {
 	gp.log("EndBackground")
 	gp.emit(&events.BackgroundEndEvent{})
 }
 
-func (gp *gherkinPegBase) beginScenario(title, description string, tags []string) {
+func (gp *gherkinPegBase) beginScenario(title, description string, tags []string, tagOffsets []int, titleOffset int) {
 	gp.log("BeginScenario: %#v: %#v tags:%+v", title, description, tags)
-	gp.emit(&events.ScenarioEvent{title, description, tags})
+	pos := gp.lines.keywordPosition(titleOffset, "Scenario:")
+	gp.emit(&events.ScenarioEvent{Title: title, Description: description, Tags: tags, TagPositions: gp.tagPositions(tagOffsets), Pos: pos})
 }
 func (gp *gherkinPegBase) endScenario() {
 	gp.log("EndScenario")
 	gp.emit(&events.ScenarioEndEvent{})
 }
 
-func (gp *gherkinPegBase) beginOutline(title, description string, tags []string) {
+func (gp *gherkinPegBase) beginOutline(title, description string, tags []string, tagOffsets []int, titleOffset int) {
 	gp.log("BeginOutline: %#v: %#v tags:%+v", title, description, tags)
-	gp.emit(&events.OutlineEvent{title, description, tags})
+	pos := gp.lines.keywordPosition(titleOffset, "Scenario Outline:")
+	titlePos := gp.lines.position(gp.lines.skipWS(titleOffset))
+	gp.emit(&events.OutlineEvent{Title: title, Description: description, Tags: tags, TagPositions: gp.tagPositions(tagOffsets), Pos: pos, TitlePos: titlePos})
 }
 func (gp *gherkinPegBase) endOutline() {
 	gp.log("EndOutline")
 	gp.emit(&events.OutlineEndEvent{})
 }
 
-func (gp *gherkinPegBase) beginOutlineExamples(title string) {
+func (gp *gherkinPegBase) beginOutlineExamples(title string, titleOffset int) {
 	gp.log("BeginOutlineExamples")
-	gp.emit(&events.OutlineExamplesEvent{title})
+	pos := gp.lines.keywordPosition(titleOffset, "Examples:")
+	gp.emit(&events.OutlineExamplesEvent{Title: title, Pos: pos})
 }
 func (gp *gherkinPegBase) endOutlineExamples() {
 	gp.log("EndOutlineExamples")
 	gp.emit(&events.OutlineExamplesEndEvent{})
 }
 
-func (gp *gherkinPegBase) beginStep(stepType, name string) {
+func (gp *gherkinPegBase) beginStep(stepType, name string, offset int) {
 	gp.log("BeginStep: %#v: %#v", stepType, name)
-	gp.emit(&events.StepEvent{stepType, name})
+	pos := gp.lines.position(offset)
+	textPos := gp.lines.position(gp.lines.skipWS(offset + utf8.RuneCountInString(stepType)))
+	gp.emit(&events.StepEvent{StepType: stepType, Text: name, Pos: pos, TextPos: textPos})
 }
 func (gp *gherkinPegBase) endStep() {
 	gp.log("EndStep")
 	gp.emit(&events.StepEndEvent{})
 }
 
-func (gp *gherkinPegBase) beginPyString(indent string) {
+func (gp *gherkinPegBase) beginPyString(indent string, offset int, mediaType string) {
 	width := len(trimNL(indent))
-	gp.log("BeginPyString: indent=%d", width)
-	gp.emit(&events.PyStringEvent{indent})
+	gp.log("BeginPyString: indent=%d mediaType=%q", width, mediaType)
+	pos := gp.lines.position(gp.lines.skipWS(offset))
+	gp.emit(&events.PyStringEvent{Intent: indent, MediaType: mediaType, Pos: pos})
+}
+
+// openPyString handles the opening line of a PyString, its indentation
+// followed by """ and the media type, if any.
+func (gp *gherkinPegBase) openPyString(line string, offset int) {
+	i := strings.Index(line, `"""`)
+	gp.beginPyString(line[:i], offset, trimWS(line[i+3:]))
 }
-func (gp *gherkinPegBase) bufferPyString(line string) {
+func (gp *gherkinPegBase) bufferPyString(line string, offset int) {
 	gp.log("BufferPyString: %#v", line)
-	gp.emit(&events.PyStringLineEvent{line})
+	pos := gp.lines.position(offset)
+	gp.emit(&events.PyStringLineEvent{Line: line, Pos: pos})
 	/*
 		indent := gp.pyString.indent
 		prefix, suffix := line[:indent], line[indent:]
@@ -158,20 +385,32 @@ func (gp *gherkinPegBase) endPyString() {
 	gp.emit(&events.PyStringEndEvent{})
 }
 
+// The table and row events are held back until the first cell is seen, as
+// that is where their position becomes known.
 func (gp *gherkinPegBase) beginTable() {
 	gp.log("BeginTable")
-	gp.emit(&events.TableEvent{})
+	gp.pendingTable = true
 }
 func (gp *gherkinPegBase) beginTableRow() {
 	gp.log("BeginTableRow")
-	gp.emit(&events.TableRowEvent{})
+	gp.pendingTableRow = true
 }
 func (gp *gherkinPegBase) beginTableCell() {
 	gp.log("BeginTableCell")
 }
-func (gp *gherkinPegBase) endTableCell(buf string) {
+func (gp *gherkinPegBase) endTableCell(buf string, offset int) {
+	if gp.pendingTable || gp.pendingTableRow {
+		pos := gp.lines.position(offset - 1)
+		if gp.pendingTable {
+			gp.emit(&events.TableEvent{Pos: pos})
+		}
+		gp.emit(&events.TableRowEvent{Pos: pos})
+		gp.pendingTable, gp.pendingTableRow = false, false
+	}
+	buf = trimWS(buf)
 	gp.log("EndTableCell: %#v", buf)
-	gp.emit(&events.TableCellEvent{buf})
+	pos := gp.lines.position(gp.lines.skipWS(offset))
+	gp.emit(&events.TableCellEvent{Content: buf, Pos: pos})
 }
 func (gp *gherkinPegBase) endTableRow() {
 	gp.log("EndTableRow")
@@ -182,9 +421,10 @@ func (gp *gherkinPegBase) endTable() {
 	gp.emit(&events.TableEndEvent{})
 }
 
-func (gp *gherkinPegBase) triggerComment(comment string) {
+func (gp *gherkinPegBase) triggerComment(comment string, offset int) {
 	gp.log("triggerComment")
-	gp.emit(&events.CommentEvent{comment})
+	pos := gp.lines.position(offset - 1)
+	gp.emit(&events.CommentEvent{Comment: comment, Pos: pos})
 }
 func (gp *gherkinPegBase) triggerBlankLine() {
 	gp.log("triggerBlankLine")
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
 	"github.com/muhqu/go-gherkin/nodes"
 	"github.com/stretchr/testify/assert"
 )
@@ -536,3 +537,133 @@ func TestParsingExampleWithTitle(t *testing.T) {
 	outline := feature.Scenarios()[0].(nodes.OutlineNode)
 	assert.Equal(t, "Attempt to withdraw too much", outline.Examples().Title())
 }
+
+func TestParsingPositions(t *testing.T) {
+	gp := mustDomParse(t, "", `@dead @simple Feature: Dead Simple Calculator
+  Bla Bla
+
+  Background:
+    Given a Simple Calculator
+
+  @wip
+  Scenario Outline: Simple Math
+     When I press the key "<left>"
+     Then the result should be "<result>" # comment
+
+    Examples:
+     | left | result |
+     |   2  | 4      |
+
+  Scenario: Adding 3 numbers
+     When I press the following keys:
+       """
+       2 + 2
+       """
+`)
+
+	feature := gp.Feature()
+	assert.Equal(t, events.Position{Offset: 14, Line: 1, Column: 15}, feature.Position())
+	assert.Equal(t, []events.Position{{Offset: 0, Line: 1, Column: 1}, {Offset: 6, Line: 1, Column: 7}}, feature.TagPositions())
+	assert.Equal(t, "4:3", feature.Background().Position().String())
+	assert.Equal(t, "5:5", feature.Background().Steps()[0].Position().String())
+
+	outline := feature.Scenarios()[0].(nodes.OutlineNode)
+	assert.Equal(t, "8:3", outline.Position().String())
+	assert.Equal(t, "7:3", outline.TagPositions()[0].String())
+	assert.Equal(t, "9:6", outline.Steps()[0].Position().String())
+	assert.Equal(t, "10:6", outline.Steps()[1].Position().String())
+	assert.Equal(t, "10:43", outline.Steps()[1].Comment().Position().String())
+	assert.Equal(t, "12:5", outline.Examples().Position().String())
+	assert.Equal(t, "13:6", outline.Examples().Table().Position().String())
+	table := outline.Examples().Table()
+	assert.Equal(t, "14:6", table.RowPositions()[1].String())
+	assert.Equal(t, "13:8", table.CellPositions()[0][0].String())
+	assert.Equal(t, "14:10", table.CellPositions()[1][0].String())
+
+	scenario := feature.Scenarios()[1]
+	assert.Equal(t, "16:3", scenario.Position().String())
+	assert.Equal(t, "18:8", scenario.Steps()[0].PyString().Position().String())
+}
+
+func TestParsingPositionsOfEvents(t *testing.T) {
+	gp := gherkin.NewGherkinParser("Feature: Ünïcödé\n  Scenario: ä\n    Given a table\n      | äb | c |\n")
+	var positions []string
+	gp.WithEventProcessor(gherkin.EventProcessorFn(func(e gherkin.GherkinEvent) {
+		if e.Position().IsValid() {
+			positions = append(positions, fmt.Sprintf("%T@%s+%d", e, e.Position(), e.Position().Offset))
+		}
+	}))
+	gp.Init()
+	assert.NoError(t, gp.Parse())
+	gp.Execute()
+
+	assert.Equal(t, []string{
+		"*events.FeatureEvent@1:1+0",
+		"*events.ScenarioEvent@2:3+23",
+		"*events.StepEvent@3:5+40",
+		"*events.TableEvent@4:7+60",
+		"*events.TableRowEvent@4:7+60",
+		"*events.TableCellEvent@4:9+62",
+		"*events.TableCellEvent@4:14+68",
+	}, positions)
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	for content, expected := range map[string]string{
+		"Scenario: without feature\n":                                   `1:1: unexpected "Scenario: without feature"`,
+		"Feature: F\n  Scenario: S\n    Given x\n    | a | b\n":         `4:5: unexpected "| a | b"`,
+		"Feature: F\n  Scenario: S\n    Given x\n    \"\"\"\n    foo\n": "6:1: unexpected end of input",
+	} {
+		_, err := gherkin.ParseGherkinFeature(content)
+		if assert.Error(t, err, content) {
+			e := err.(*gherkin.ParseError)
+			assert.Equal(t, expected, e.Position().String()+": "+e.Msg, content)
+			if assert.NotNil(t, e.Unwrap(), content) {
+				assert.Equal(t, e.Unwrap().Error(), e.Error(), content)
+			}
+		}
+	}
+}
+
+func TestParsingKeywordTypes(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature(`
+Feature: Effective keyword types
+  Background:
+    * a calculator
+    Given it is on
+    But it is empty
+
+  Scenario: Continues the background
+    And the display is clear
+    When I press "2"
+    * I press "+"
+    Then the result is 2
+    Or it is an error
+
+  Scenario Outline: Starts anew
+    When I press "<key>"
+    And I press "="
+
+    Examples:
+      | key |
+`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var types []string
+	for _, scenario := range append([]nodes.ScenarioNode{feature.Background()}, feature.Scenarios()...) {
+		for _, step := range scenario.Steps() {
+			types = append(types, step.StepType()+":"+step.KeywordType().String())
+		}
+	}
+	assert.Equal(t, []string{
+		"*:Unknown", "Given:Context", "But:Context",
+		"And:Context", "When:Action", "*:Action", "Then:Outcome", "Or:Outcome",
+		"When:Action", "And:Action",
+	}, types)
+
+	step := nodes.NewMutableStepNode("When", "x")
+	step.SetStepType("And")
+	assert.Equal(t, nodes.ActionKeywordType, step.KeywordType())
+	assert.Equal(t, nodes.UnknownKeywordType, nodes.NewMutableStepNode("*", "x").KeywordType())
+}
@@ -0,0 +1,126 @@
+package gherkin
+
+import (
+	"github.com/muhqu/go-gherkin/events"
+)
+
+// EventHandler has a method for each type of event, which gets the fields of
+// the event as arguments. Use EventHandlerProcessor to register it with a
+// parser and embed NopEventHandler to implement just the methods of interest.
+//
+// The End events and blank lines have no position yet, pos is the zero
+// Position for them.
+type EventHandler interface {
+	BeginFeature(title, description string, tags []string, tagPositions []events.Position, pos events.Position)
+	EndFeature(pos events.Position)
+	BeginBackground(title, description string, tags []string, tagPositions []events.Position, pos events.Position)
+	EndBackground(pos events.Position)
+	BeginScenario(title, description string, tags []string, tagPositions []events.Position, pos events.Position)
+	EndScenario(pos events.Position)
+	BeginOutline(title, description string, tags []string, tagPositions []events.Position, pos, titlePos events.Position)
+	EndOutline(pos events.Position)
+	BeginOutlineExamples(title string, pos events.Position)
+	EndOutlineExamples(pos events.Position)
+	BeginStep(stepType, text string, pos, textPos events.Position)
+	EndStep(pos events.Position)
+	BeginPyString(indent, mediaType string, pos events.Position)
+	PyStringLine(line string, pos events.Position)
+	EndPyString(pos events.Position)
+	BeginTable(pos events.Position)
+	BeginTableRow(pos events.Position)
+	TableCell(content string, pos events.Position)
+	EndTableRow(pos events.Position)
+	EndTable(pos events.Position)
+	BlankLine(pos events.Position)
+	Comment(comment string, pos events.Position)
+}
+
+// NopEventHandler implements all methods of EventHandler, doing nothing.
+type NopEventHandler struct{}
+
+func (NopEventHandler) BeginFeature(title, description string, tags []string, tagPositions []events.Position, pos events.Position) {
+}
+func (NopEventHandler) EndFeature(pos events.Position) {}
+func (NopEventHandler) BeginBackground(title, description string, tags []string, tagPositions []events.Position, pos events.Position) {
+}
+func (NopEventHandler) EndBackground(pos events.Position) {}
+func (NopEventHandler) BeginScenario(title, description string, tags []string, tagPositions []events.Position, pos events.Position) {
+}
+func (NopEventHandler) EndScenario(pos events.Position) {}
+func (NopEventHandler) BeginOutline(title, description string, tags []string, tagPositions []events.Position, pos, titlePos events.Position) {
+}
+func (NopEventHandler) EndOutline(pos events.Position)                                {}
+func (NopEventHandler) BeginOutlineExamples(title string, pos events.Position)        {}
+func (NopEventHandler) EndOutlineExamples(pos events.Position)                        {}
+func (NopEventHandler) BeginStep(stepType, text string, pos, textPos events.Position) {}
+func (NopEventHandler) EndStep(pos events.Position)                                   {}
+func (NopEventHandler) BeginPyString(indent, mediaType string, pos events.Position)   {}
+func (NopEventHandler) PyStringLine(line string, pos events.Position)                 {}
+func (NopEventHandler) EndPyString(pos events.Position)                               {}
+func (NopEventHandler) BeginTable(pos events.Position)                                {}
+func (NopEventHandler) BeginTableRow(pos events.Position)                             {}
+func (NopEventHandler) TableCell(content string, pos events.Position)                 {}
+func (NopEventHandler) EndTableRow(pos events.Position)                               {}
+func (NopEventHandler) EndTable(pos events.Position)                                  {}
+func (NopEventHandler) BlankLine(pos events.Position)                                 {}
+func (NopEventHandler) Comment(comment string, pos events.Position)                   {}
+
+// EventHandlerProcessor returns an EventProcessor calling the method of h for
+// each event.
+func EventHandlerProcessor(h EventHandler) EventProcessor {
+	return &eventHandlerProcessor{h}
+}
+
+type eventHandlerProcessor struct {
+	h EventHandler
+}
+
+func (p *eventHandlerProcessor) ProcessEvent(event GherkinEvent) {
+	h := p.h
+	switch e := event.(type) {
+	case *events.FeatureEvent:
+		h.BeginFeature(e.Title, e.Description, e.Tags, e.TagPositions, e.Pos)
+	case *events.FeatureEndEvent:
+		h.EndFeature(e.Pos)
+	case *events.BackgroundEvent:
+		h.BeginBackground(e.Title, e.Description, e.Tags, e.TagPositions, e.Pos)
+	case *events.BackgroundEndEvent:
+		h.EndBackground(e.Pos)
+	case *events.ScenarioEvent:
+		h.BeginScenario(e.Title, e.Description, e.Tags, e.TagPositions, e.Pos)
+	case *events.ScenarioEndEvent:
+		h.EndScenario(e.Pos)
+	case *events.OutlineEvent:
+		h.BeginOutline(e.Title, e.Description, e.Tags, e.TagPositions, e.Pos, e.TitlePos)
+	case *events.OutlineEndEvent:
+		h.EndOutline(e.Pos)
+	case *events.OutlineExamplesEvent:
+		h.BeginOutlineExamples(e.Title, e.Pos)
+	case *events.OutlineExamplesEndEvent:
+		h.EndOutlineExamples(e.Pos)
+	case *events.StepEvent:
+		h.BeginStep(e.StepType, e.Text, e.Pos, e.TextPos)
+	case *events.StepEndEvent:
+		h.EndStep(e.Pos)
+	case *events.PyStringEvent:
+		h.BeginPyString(e.Intent, e.MediaType, e.Pos)
+	case *events.PyStringLineEvent:
+		h.PyStringLine(e.Line, e.Pos)
+	case *events.PyStringEndEvent:
+		h.EndPyString(e.Pos)
+	case *events.TableEvent:
+		h.BeginTable(e.Pos)
+	case *events.TableRowEvent:
+		h.BeginTableRow(e.Pos)
+	case *events.TableCellEvent:
+		h.TableCell(e.Content, e.Pos)
+	case *events.TableRowEndEvent:
+		h.EndTableRow(e.Pos)
+	case *events.TableEndEvent:
+		h.EndTable(e.Pos)
+	case *events.BlankLineEvent:
+		h.BlankLine(e.Pos)
+	case *events.CommentEvent:
+		h.Comment(e.Comment, e.Pos)
+	}
+}
@@ -0,0 +1,130 @@
+package gherkin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type stepPrinter struct {
+	gherkin.NopEventHandler
+}
+
+func (stepPrinter) BeginStep(stepType, text string, pos, textPos events.Position) {
+	fmt.Printf("%s: %s %s\n", pos, stepType, text)
+}
+
+func ExampleEventHandlerProcessor() {
+	gp := gherkin.NewGherkinParser(calculatorGherkin)
+	gp.WithEventProcessor(gherkin.EventHandlerProcessor(stepPrinter{}))
+	gp.Init()
+	gp.Parse()
+	gp.Execute()
+
+	// Output:
+	// 6:5: Given a calculator
+	// 10:5: When I add <a> and <b>
+	// 11:5: Then the result is <sum>
+	// 18:5: When I type:
+	// 24:5: And I press the keys:
+	// 27:5: Then the result is 4
+}
+
+// eventRebuilder turns the calls back into events.
+type eventRebuilder struct {
+	events []gherkin.GherkinEvent
+}
+
+func (r *eventRebuilder) add(e gherkin.GherkinEvent) {
+	r.events = append(r.events, e)
+}
+
+func (r *eventRebuilder) BeginFeature(title, description string, tags []string, tagPositions []events.Position, pos events.Position) {
+	r.add(&events.FeatureEvent{Title: title, Description: description, Tags: tags, TagPositions: tagPositions, Pos: pos})
+}
+func (r *eventRebuilder) EndFeature(pos events.Position) {
+	r.add(&events.FeatureEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) BeginBackground(title, description string, tags []string, tagPositions []events.Position, pos events.Position) {
+	r.add(&events.BackgroundEvent{Title: title, Description: description, Tags: tags, TagPositions: tagPositions, Pos: pos})
+}
+func (r *eventRebuilder) EndBackground(pos events.Position) {
+	r.add(&events.BackgroundEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) BeginScenario(title, description string, tags []string, tagPositions []events.Position, pos events.Position) {
+	r.add(&events.ScenarioEvent{Title: title, Description: description, Tags: tags, TagPositions: tagPositions, Pos: pos})
+}
+func (r *eventRebuilder) EndScenario(pos events.Position) {
+	r.add(&events.ScenarioEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) BeginOutline(title, description string, tags []string, tagPositions []events.Position, pos, titlePos events.Position) {
+	r.add(&events.OutlineEvent{Title: title, Description: description, Tags: tags, TagPositions: tagPositions, Pos: pos, TitlePos: titlePos})
+}
+func (r *eventRebuilder) EndOutline(pos events.Position) {
+	r.add(&events.OutlineEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) BeginOutlineExamples(title string, pos events.Position) {
+	r.add(&events.OutlineExamplesEvent{Title: title, Pos: pos})
+}
+func (r *eventRebuilder) EndOutlineExamples(pos events.Position) {
+	r.add(&events.OutlineExamplesEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) BeginStep(stepType, text string, pos, textPos events.Position) {
+	r.add(&events.StepEvent{StepType: stepType, Text: text, Pos: pos, TextPos: textPos})
+}
+func (r *eventRebuilder) EndStep(pos events.Position) {
+	r.add(&events.StepEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) BeginPyString(indent, mediaType string, pos events.Position) {
+	r.add(&events.PyStringEvent{Intent: indent, MediaType: mediaType, Pos: pos})
+}
+func (r *eventRebuilder) PyStringLine(line string, pos events.Position) {
+	r.add(&events.PyStringLineEvent{Line: line, Pos: pos})
+}
+func (r *eventRebuilder) EndPyString(pos events.Position) {
+	r.add(&events.PyStringEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) BeginTable(pos events.Position) {
+	r.add(&events.TableEvent{Pos: pos})
+}
+func (r *eventRebuilder) BeginTableRow(pos events.Position) {
+	r.add(&events.TableRowEvent{Pos: pos})
+}
+func (r *eventRebuilder) TableCell(content string, pos events.Position) {
+	r.add(&events.TableCellEvent{Content: content, Pos: pos})
+}
+func (r *eventRebuilder) EndTableRow(pos events.Position) {
+	r.add(&events.TableRowEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) EndTable(pos events.Position) {
+	r.add(&events.TableEndEvent{Pos: pos})
+}
+func (r *eventRebuilder) BlankLine(pos events.Position) {
+	r.add(&events.BlankLineEvent{Pos: pos})
+}
+func (r *eventRebuilder) Comment(comment string, pos events.Position) {
+	r.add(&events.CommentEvent{Comment: comment, Pos: pos})
+}
+
+func TestEventHandlerProcessorCallsAllMethods(t *testing.T) {
+	rec := gherkin.NewEventRecorder()
+	rebuilder := &eventRebuilder{}
+	gp := gherkin.NewGherkinParser(benchmarkGherkinText + "\n# end\n")
+	gp.WithEventProcessor(rec)
+	gp.WithEventProcessor(gherkin.EventHandlerProcessor(rebuilder))
+	gp.Init()
+	assert.NoError(t, gp.Parse())
+	assert.NoError(t, gp.Execute())
+
+	assert.Equal(t, rec.Events(), rebuilder.events)
+	seen := map[events.EventType]bool{}
+	for _, e := range rebuilder.events {
+		seen[e.EventType()] = true
+	}
+	assert.Len(t, seen, int(events.CommentEventType)+1)
+
+	var _ gherkin.EventHandler = gherkin.NopEventHandler{}
+}
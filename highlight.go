@@ -0,0 +1,314 @@
+package gherkin
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/muhqu/go-gherkin/events"
+)
+
+type TokenType int
+
+const (
+	KeywordToken            TokenType = iota // Feature:, Background:, Scenario:, Scenario Outline:, Examples:
+	TitleToken                               // the text following one of the keywords
+	DescriptionToken                         // a line of the free text below a title
+	TagToken                                 // @tag
+	StepKeywordToken                         // Given, When, Then, And, Or, But, *
+	StepTextToken                            // the text of a step
+	ParameterToken                           // a "quoted" parameter in a step text
+	PlaceholderToken                         // an outline <placeholder>
+	TablePipeToken                           // |
+	TableCellToken                           // the trimmed content of a table cell
+	DocStringDelimiterToken                  // """
+	DocStringToken                           // a line of a doc string, without indentation
+	CommentToken                             // # comment
+)
+
+var tokenTypeNames = []string{
+	"keyword", "title", "description", "tag", "step-keyword", "step-text", "parameter",
+	"placeholder", "table-pipe", "table-cell", "docstring-delimiter", "docstring", "comment",
+}
+
+func (t TokenType) String() string {
+	if t < 0 || int(t) >= len(tokenTypeNames) {
+		return "unknown"
+	}
+	return tokenTypeNames[t]
+}
+
+// Token is a typed span of the source, for syntax highlighting. Tokens never
+// span more than one line.
+type Token struct {
+	Type   TokenType
+	Pos    events.Position
+	Length int // in bytes
+}
+
+// End returns the byte offset following the token.
+func (t *Token) End() int {
+	return t.Pos.Offset + t.Length
+}
+
+func (t *Token) Text(content string) string {
+	return content[t.Pos.Offset:t.End()]
+}
+
+// Tokenize parses content and returns its tokens, ordered by offset. Text not
+// covered by a token, like indentation, is meant to be shown as is.
+//
+//	tokens, err := gherkin.Tokenize(content)
+//	for _, t := range tokens {
+//		fmt.Printf("%s %s %q\n", t.Pos, t.Type, t.Text(content))
+//	}
+func Tokenize(content string) ([]*Token, error) {
+	t := newTokenizer(content)
+	p := NewGherkinParser(content)
+	p.WithEventProcessor(t)
+	p.Init()
+	if err := p.Parse(); err != nil {
+		return nil, err
+	}
+	p.Execute()
+	sort.Stable(byTokenOffset(t.tokens))
+	return t.tokens, nil
+}
+
+type byTokenOffset []*Token
+
+func (s byTokenOffset) Len() int           { return len(s) }
+func (s byTokenOffset) Less(i, j int) bool { return s[i].Pos.Offset < s[j].Pos.Offset }
+func (s byTokenOffset) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// ----------------------------------------
+
+var (
+	quotedParameter = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	placeholder     = regexp.MustCompile(`<[^<>]+>`)
+)
+
+// tokenizer derives the tokens from the events of the parser, reading the
+// details the events leave out from the source.
+type tokenizer struct {
+	content    string
+	lineStarts []int // byte offset of each line
+	tokens     []*Token
+
+	inOutline   bool
+	inExamples  bool
+	pyStringEnd int // end of the last line of the current doc string
+}
+
+func newTokenizer(content string) *tokenizer {
+	t := &tokenizer{content: content, lineStarts: []int{0}}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			t.lineStarts = append(t.lineStarts, i+1)
+		}
+	}
+	return t
+}
+
+func (t *tokenizer) ProcessEvent(event GherkinEvent) {
+	switch e := event.(type) {
+	case *events.FeatureEvent:
+		t.header(e.Pos, "Feature:", e.Description, e.TagPositions)
+	case *events.BackgroundEvent:
+		t.header(e.Pos, "Background:", e.Description, e.TagPositions)
+	case *events.ScenarioEvent:
+		t.header(e.Pos, "Scenario:", e.Description, e.TagPositions)
+	case *events.OutlineEvent:
+		t.inOutline = true
+		t.header(e.Pos, "Scenario Outline:", e.Description, e.TagPositions)
+	case *events.OutlineEndEvent:
+		t.inOutline = false
+	case *events.OutlineExamplesEvent:
+		t.inExamples = true
+		t.header(e.Pos, "Examples:", "", nil)
+	case *events.OutlineExamplesEndEvent:
+		t.inExamples = false
+
+	case *events.StepEvent:
+		start, end := e.Pos.Offset, e.Pos.Offset
+		for end < len(t.content) && !strings.ContainsRune(" \t\r\n", rune(t.content[end])) {
+			end++
+		}
+		t.add(StepKeywordToken, start, end)
+		t.text(StepTextToken, end, true)
+
+	case *events.TableRowEvent:
+		t.tableRow(e.Pos.Offset)
+
+	case *events.PyStringEvent:
+		t.add(DocStringDelimiterToken, e.Pos.Offset, e.Pos.Offset+3)
+		t.pyStringEnd = t.lineEnd(e.Pos.Offset)
+	case *events.PyStringLineEvent:
+		start, end := skipWS(t.content, e.Pos.Offset), t.lineEnd(e.Pos.Offset)
+		t.span(DocStringToken, start, end, false)
+		t.pyStringEnd = end
+	case *events.PyStringEndEvent:
+		if t.pyStringEnd < len(t.content) {
+			start := skipWS(t.content, t.pyStringEnd+1)
+			if strings.HasPrefix(t.content[start:], `"""`) {
+				t.add(DocStringDelimiterToken, start, start+3)
+			}
+		}
+
+	case *events.CommentEvent:
+		t.add(CommentToken, e.Pos.Offset, t.lineEnd(e.Pos.Offset))
+	}
+}
+
+// header adds the tokens of a keyword line: its tags, keyword, title and
+// description.
+func (t *tokenizer) header(pos events.Position, keyword, description string, tags []events.Position) {
+	for _, tag := range tags {
+		end := tag.Offset + 1
+		for end < len(t.content) && !strings.ContainsRune("\r\n\t \"#", rune(t.content[end])) {
+			end++
+		}
+		t.add(TagToken, tag.Offset, end)
+	}
+	t.add(KeywordToken, pos.Offset, pos.Offset+len(keyword))
+	t.text(TitleToken, pos.Offset+len(keyword), false)
+
+	// the description lines are trimmed and blank lines are dropped, so they
+	// are looked up line by line
+	var lines []string
+	for _, line := range strings.Split(description, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	offset := t.lineEnd(pos.Offset) + 1
+	for len(lines) > 0 && offset < len(t.content) {
+		start, end := skipWS(t.content, offset), t.lineEnd(offset)
+		end = start + textEnd(t.content[start:end])
+		if text := strings.TrimRight(t.content[start:end], " \t"); text == lines[0] {
+			t.add(DescriptionToken, start, start+len(text))
+			lines = lines[1:]
+		} else if text != "" {
+			break
+		}
+		offset = t.lineEnd(offset) + 1
+	}
+}
+
+// text adds the tokens of the text starting at offset, up to a comment or the
+// end of the line.
+func (t *tokenizer) text(typ TokenType, offset int, parameters bool) {
+	start, end := skipWS(t.content, offset), t.lineEnd(offset)
+	end = start + textEnd(t.content[start:end])
+	end = start + len(strings.TrimRight(t.content[start:end], " \t"))
+	t.span(typ, start, end, parameters)
+}
+
+// span adds a token of typ for the text from start to end, split where there
+// are parameters or placeholders.
+func (t *tokenizer) span(typ TokenType, start, end int, parameters bool) {
+	if start >= end {
+		return
+	}
+	text := t.content[start:end]
+	types := make([]TokenType, len(text))
+	for i := range types {
+		types[i] = typ
+	}
+	mark := func(re *regexp.Regexp, typ TokenType) {
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			for i := loc[0]; i < loc[1]; i++ {
+				types[i] = typ
+			}
+		}
+	}
+	if parameters {
+		mark(quotedParameter, ParameterToken)
+	}
+	if t.inOutline && !t.inExamples {
+		mark(placeholder, PlaceholderToken)
+	}
+	from := 0
+	for i := 1; i <= len(types); i++ {
+		if i == len(types) || types[i] != types[from] {
+			t.add(types[from], start+from, start+i)
+			from = i
+		}
+	}
+}
+
+// tableRow adds the pipes and cells of the row starting at offset.
+func (t *tokenizer) tableRow(offset int) {
+	end := t.lineEnd(offset)
+	cell := -1
+	for i := offset; i < end; i++ {
+		if t.content[i] != '|' {
+			continue
+		}
+		if cell >= 0 {
+			start := skipWS(t.content, cell)
+			t.span(TableCellToken, start, start+len(strings.TrimRight(t.content[start:i], " \t")), false)
+		}
+		t.add(TablePipeToken, i, i+1)
+		cell = i + 1
+	}
+}
+
+func (t *tokenizer) add(typ TokenType, start, end int) {
+	if end > len(t.content) {
+		end = len(t.content)
+	}
+	if start >= end {
+		return
+	}
+	t.tokens = append(t.tokens, &Token{Type: typ, Pos: t.position(start), Length: end - start})
+}
+
+func (t *tokenizer) position(offset int) events.Position {
+	line := sort.Search(len(t.lineStarts), func(i int) bool { return t.lineStarts[i] > offset }) - 1
+	start := t.lineStarts[line]
+	return events.Position{Offset: offset, Line: line + 1, Column: utf8.RuneCountInString(t.content[start:offset]) + 1}
+}
+
+// lineEnd returns the offset of the end of the line at offset, before any
+// "\r\n" or "\n".
+func (t *tokenizer) lineEnd(offset int) int {
+	end := len(t.content)
+	if offset > end {
+		return end
+	}
+	if i := strings.IndexByte(t.content[offset:], '\n'); i >= 0 {
+		end = offset + i
+	}
+	if end > offset && t.content[end-1] == '\r' {
+		end--
+	}
+	return end
+}
+
+func skipWS(str string, offset int) int {
+	for offset < len(str) && (str[offset] == ' ' || str[offset] == '\t') {
+		offset++
+	}
+	return offset
+}
+
+// textEnd returns where a comment starts in line, following the grammar's
+// UntilLineEnd: '#' does not start a comment within quotes or when escaped.
+func textEnd(line string) int {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case '#':
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return len(line)
+}
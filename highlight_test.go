@@ -0,0 +1,101 @@
+package gherkin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleTokenize() {
+	content := `@calc
+Feature: Calculator # of the basics
+  Adds numbers
+
+  Scenario Outline: Adding <a>
+    Given I press "<a>" and "+"
+    Then the result is <sum>
+      """
+      sum: <sum>
+      """
+
+    Examples:
+      | a | sum |
+      | 1 | 2   |
+`
+	tokens, _ := gherkin.Tokenize(content)
+	for _, t := range tokens {
+		fmt.Printf("%s %s %q\n", t.Pos, t.Type, t.Text(content))
+	}
+
+	// Output:
+	// 1:1 tag "@calc"
+	// 2:1 keyword "Feature:"
+	// 2:10 title "Calculator"
+	// 2:21 comment "# of the basics"
+	// 3:3 description "Adds numbers"
+	// 5:3 keyword "Scenario Outline:"
+	// 5:21 title "Adding "
+	// 5:28 placeholder "<a>"
+	// 6:5 step-keyword "Given"
+	// 6:11 step-text "I press "
+	// 6:19 parameter "\""
+	// 6:20 placeholder "<a>"
+	// 6:23 parameter "\""
+	// 6:24 step-text " and "
+	// 6:29 parameter "\"+\""
+	// 7:5 step-keyword "Then"
+	// 7:10 step-text "the result is "
+	// 7:24 placeholder "<sum>"
+	// 8:7 docstring-delimiter "\"\"\""
+	// 9:7 docstring "sum: "
+	// 9:12 placeholder "<sum>"
+	// 10:7 docstring-delimiter "\"\"\""
+	// 12:5 keyword "Examples:"
+	// 13:7 table-pipe "|"
+	// 13:9 table-cell "a"
+	// 13:11 table-pipe "|"
+	// 13:13 table-cell "sum"
+	// 13:17 table-pipe "|"
+	// 14:7 table-pipe "|"
+	// 14:9 table-cell "1"
+	// 14:11 table-pipe "|"
+	// 14:13 table-cell "2"
+	// 14:17 table-pipe "|"
+}
+
+func TestTokenizeCoversSource(t *testing.T) {
+	content := "Feature: Ünïcode\r\n  Scenario: S # c\r\n    * a \"x # y\" \\# z\r\n      | ä | b | # row\r\n"
+	tokens, err := gherkin.Tokenize(content)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var texts []string
+	for _, tok := range tokens {
+		text := tok.Text(content)
+		assert.NotContains(t, text, "\n")
+		texts = append(texts, tok.Type.String()+" "+text)
+	}
+	assert.Equal(t, []string{
+		"keyword Feature:",
+		"title Ünïcode",
+		"keyword Scenario:",
+		"title S",
+		"comment # c",
+		"step-keyword *",
+		"step-text a ",
+		`parameter "x # y"`,
+		`step-text  \# z`,
+		"table-pipe |",
+		"table-cell ä",
+		"table-pipe |",
+		"table-cell b",
+		"table-pipe |",
+		"comment # row",
+	}, texts)
+	assert.Equal(t, 17, tokens[len(tokens)-1].Pos.Column)
+
+	_, err = gherkin.Tokenize("Scenario: without feature\n")
+	assert.Error(t, err)
+}
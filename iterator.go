@@ -0,0 +1,51 @@
+package gherkin
+
+import (
+	"io"
+)
+
+// EventIterator is the pull counterpart of the EventProcessor: the events are
+// parsed as Next is called, reading no more input than needed. Consumers that
+// are done, e.g. after the FeatureEvent, just stop calling Next.
+type EventIterator interface {
+	// Next returns the next event, or io.EOF after the last one. Errors of the
+	// reader are returned as they are, syntax errors as *ParseError. Once an
+	// error is returned, Next keeps returning it.
+	Next() (GherkinEvent, error)
+}
+
+// NewEventIterator returns an EventIterator parsing the Gherkin read from r,
+// the same way the GherkinStreamParser does.
+func NewEventIterator(r io.Reader) EventIterator {
+	it := &eventIterator{}
+	it.sp = NewGherkinStreamParser(r).(*gherkinStreamParser)
+	it.sp.WithEventProcessor(it)
+	return it
+}
+
+type eventIterator struct {
+	sp     *gherkinStreamParser
+	events []GherkinEvent // of the last line, up to the next one
+	err    error
+}
+
+func (it *eventIterator) ProcessEvent(e GherkinEvent) {
+	it.events = append(it.events, e)
+}
+
+func (it *eventIterator) Next() (GherkinEvent, error) {
+	for len(it.events) == 0 {
+		if it.err != nil {
+			return nil, it.err
+		}
+		if it.sp.eof {
+			it.err = io.EOF
+		} else {
+			it.err = it.sp.next()
+		}
+	}
+	e := it.events[0]
+	it.events[0] = nil
+	it.events = it.events[1:]
+	return e, nil
+}
@@ -0,0 +1,101 @@
+package gherkin_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleNewEventIterator() {
+	it := gherkin.NewEventIterator(strings.NewReader(`@wip
+Feature: Hello World
+  Scenario: Nice people
+    Given a nice person called "Bob"
+`))
+	for {
+		e, err := it.Next()
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if feature, ok := e.(*events.FeatureEvent); ok {
+			fmt.Println(feature.Title, feature.Tags)
+			return
+		}
+	}
+
+	// Output:
+	// Hello World [wip]
+}
+
+func TestEventIteratorEmitsSameEvents(t *testing.T) {
+	sp := gherkin.NewGherkinStreamParser(strings.NewReader(benchmarkGherkinText))
+	expected := recordEvents(sp.WithEventProcessor)
+	assert.NoError(t, sp.Parse())
+
+	var actual []gherkin.GherkinEvent
+	it := gherkin.NewEventIterator(strings.NewReader(benchmarkGherkinText))
+	for {
+		e, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+		actual = append(actual, e)
+	}
+	assert.Equal(t, *expected, actual)
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestEventIteratorReadsLazily(t *testing.T) {
+	failure := errors.New("no more")
+	r := io.MultiReader(strings.NewReader("Feature: F\n  Scenario: S\n    Given x\n"), failingReader{failure})
+	it := gherkin.NewEventIterator(r)
+
+	e, err := it.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "F", e.(*events.FeatureEvent).Title)
+	e, err = it.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "S", e.(*events.ScenarioEvent).Title)
+	e, err = it.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, "x", e.(*events.StepEvent).Text)
+
+	_, err = it.Next()
+	assert.Equal(t, failure, err)
+	_, err = it.Next()
+	assert.Equal(t, failure, err)
+}
+
+func TestEventIteratorErrors(t *testing.T) {
+	it := gherkin.NewEventIterator(strings.NewReader("Feature: F\n  Scenario: S # c\n    Given x\n  | a\n"))
+	var types []string
+	var err error
+	for err == nil {
+		var e gherkin.GherkinEvent
+		if e, err = it.Next(); err == nil {
+			types = append(types, fmt.Sprintf("%T", e))
+		}
+	}
+	assert.Equal(t, []string{"*events.FeatureEvent", "*events.CommentEvent", "*events.ScenarioEvent", "*events.StepEvent"}, types)
+	if e, ok := err.(*gherkin.ParseError); assert.True(t, ok) {
+		assert.Equal(t, 4, e.Pos.Line)
+	}
+	_, next := it.Next()
+	assert.Equal(t, err, next)
+
+	_, err = gherkin.NewEventIterator(strings.NewReader("")).Next()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,127 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type registeredRule struct {
+	newRule  func() Rule
+	severity Severity
+}
+
+var registered []registeredRule
+
+// Register adds a rule created by newRule to linters created by NewLinter from
+// now on. Each linter gets its own rule, so configuring it does not affect
+// other linters. Register is meant to be called from init functions.
+//
+//	func init() {
+//		lint.Register(func() lint.Rule { return &TeamTag{Prefix: "team-"} }, lint.SeverityError)
+//	}
+func Register(newRule func() Rule, severity Severity) {
+	registered = append(registered, registeredRule{newRule, severity})
+}
+
+// ParseSeverity returns the severity named s, as returned by Severity.String.
+func ParseSeverity(s string) (Severity, error) {
+	for _, severity := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		if severity.String() == s {
+			return severity, nil
+		}
+	}
+	return SeverityInfo, fmt.Errorf("unknown severity %q", s)
+}
+
+// Configurable is implemented by rules handling their options themselves.
+// The options of other rules are unmarshaled into the rule, so exported
+// fields like TooManySteps.Max can be set without further ado. Keys matching
+// none of the fields are an error, rather than a typo going unnoticed.
+type Configurable interface {
+	Configure(options json.RawMessage) error
+}
+
+// Config is the configuration of the rules of a linter, usually read from a
+// JSON file.
+//
+//	{
+//	  "rules": {
+//	    "single-step-background": {"enabled": false},
+//	    "too-many-steps": {"severity": "error", "options": {"max": 5}},
+//	    "disallowed-tags": {"options": {"tags": ["@wip"]}}
+//	  }
+//	}
+type Config struct {
+	Rules map[string]*RuleConfig `json:"rules"`
+}
+
+type RuleConfig struct {
+	Enabled  *bool           `json:"enabled,omitempty"`
+	Severity string          `json:"severity,omitempty"`
+	Options  json.RawMessage `json:"options,omitempty"`
+}
+
+func ReadConfig(r io.Reader) (*Config, error) {
+	config := &Config{}
+	if err := json.NewDecoder(r).Decode(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	config, err := ReadConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return config, nil
+}
+
+// Apply configures the rules of linter, in the order of their names. All rules
+// named by the config have to be known to linter.
+func (c *Config) Apply(linter Linter) error {
+	var names []string
+	for name := range c.Rules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rc := c.Rules[name]
+		rule := linter.Rule(name)
+		if rule == nil {
+			return fmt.Errorf("unknown lint rule %q", name)
+		}
+		if len(rc.Options) > 0 {
+			var err error
+			if configurable, ok := rule.(Configurable); ok {
+				err = configurable.Configure(rc.Options)
+			} else {
+				err = decodeOptions(rc.Options, rule)
+			}
+			if err != nil {
+				return fmt.Errorf("options of lint rule %q: %s", name, err)
+			}
+		}
+		if rc.Severity != "" {
+			severity, err := ParseSeverity(rc.Severity)
+			if err != nil {
+				return fmt.Errorf("lint rule %q: %s", name, err)
+			}
+			linter.SetSeverity(name, severity)
+		}
+		if rc.Enabled != nil && *rc.Enabled {
+			linter.Enable(name)
+		} else if rc.Enabled != nil {
+			linter.Disable(name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package lint_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/lint"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/stretchr/testify/assert"
+)
+
+// TeamTag requires every scenario to carry exactly one tag with Prefix.
+type TeamTag struct {
+	Prefix string `json:"prefix"`
+}
+
+func (*TeamTag) Name() string { return "team-tag" }
+
+func (rule *TeamTag) Check(feature nodes.FeatureNode, r lint.Reporter) {
+	if feature == nil {
+		return
+	}
+	for _, scenario := range feature.Scenarios() {
+		n := 0
+		for _, tag := range scenario.Tags() {
+			if strings.HasPrefix(tag, rule.Prefix) {
+				n++
+			}
+		}
+		if n != 1 {
+			r.Report(scenario.Position(), "scenario has %d @%s* tags, expected one", n, rule.Prefix)
+		}
+	}
+}
+
+// JiraKey checks the keys of @JIRA- tags, the pattern being configured as a
+// plain JSON string.
+type JiraKey struct {
+	pattern *regexp.Regexp
+}
+
+func (*JiraKey) Name() string { return "jira-key" }
+
+func (rule *JiraKey) Configure(options json.RawMessage) error {
+	var pattern string
+	if err := json.Unmarshal(options, &pattern); err != nil {
+		return err
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err == nil {
+		rule.pattern = re
+	}
+	return err
+}
+
+func (rule *JiraKey) Check(feature nodes.FeatureNode, r lint.Reporter) {
+	if feature == nil || rule.pattern == nil {
+		return
+	}
+	for _, scenario := range feature.Scenarios() {
+		for i, tag := range scenario.Tags() {
+			if strings.HasPrefix(tag, "JIRA-") && !rule.pattern.MatchString(tag[5:]) {
+				r.Report(scenario.TagPositions()[i], "malformed JIRA key %q", tag[5:])
+			}
+		}
+	}
+}
+
+const teamFeature = `Feature: Checkout
+
+  @team-payments
+  Scenario: Paying by card
+    Given a cart
+
+  Scenario: Paying by invoice
+    Given a cart
+`
+
+func Example_customRule() {
+	l := lint.NewLinter()
+	l.AddRule(&TeamTag{Prefix: "team-"}, lint.SeverityError)
+
+	feature, _ := gherkin.ParseGherkinFeature(teamFeature)
+	for _, d := range l.Lint(feature) {
+		fmt.Println(d)
+	}
+	// Output:
+	// 7:3: error: scenario has 0 @team-* tags, expected one (team-tag)
+}
+
+func TestRegister(t *testing.T) {
+	defer lint.SaveRegistered()()
+	lint.Register(func() lint.Rule { return &TeamTag{Prefix: "team-"} }, lint.SeverityError)
+
+	l1, l2 := lint.NewLinter(), lint.NewLinter()
+	l2.Rule("team-tag").(*TeamTag).Prefix = "squad-"
+	assert.Equal(t, []string{"7:3: error: scenario has 0 @team-* tags, expected one (team-tag)"}, lintStrings(l1, teamFeature))
+	assert.Len(t, lintStrings(l2, teamFeature), 2)
+}
+
+func TestRegisterRestored(t *testing.T) {
+	restore := lint.SaveRegistered()
+	lint.Register(func() lint.Rule { return &TeamTag{} }, lint.SeverityError)
+	restore()
+	assert.Nil(t, lint.NewLinter().Rule("team-tag"))
+}
+
+const jiraFeature = `Feature: Checkout
+
+  @JIRA-12 @JIRA-SHOP-12
+  Scenario: Paying by card
+    Given a cart
+    And a card
+    And an address
+    When I pay
+`
+
+func TestConfigApply(t *testing.T) {
+	config, err := lint.ReadConfig(strings.NewReader(`{
+		"rules": {
+			"single-step-background": {"enabled": false},
+			"jira-key": {"severity": "warning", "options": "[A-Z]+-[0-9]+"},
+			"too-many-steps": {"severity": "error", "options": {"max": 3}}
+		}
+	}`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	l := lint.NewLinter()
+	l.AddRule(&JiraKey{}, lint.SeverityError)
+	assert.NoError(t, config.Apply(l))
+	assert.Equal(t, 3, l.Rule("too-many-steps").(*lint.TooManySteps).Max)
+	assert.Equal(t, []string{
+		`3:3: warning: malformed JIRA key "12" (jira-key)`,
+		"4:3: error: scenario has 4 steps, more than 3 (too-many-steps)",
+	}, lintStrings(l, jiraFeature))
+}
+
+func TestConfigErrors(t *testing.T) {
+	for content, expected := range map[string]string{
+		`{"rules": {"no-such-rule": {}}}`:                             `unknown lint rule "no-such-rule"`,
+		`{"rules": {"missing-title": {"severity": "fatal"}}}`:         `lint rule "missing-title": unknown severity "fatal"`,
+		`{"rules": {"too-many-steps": {"options": {"max": "five"}}}}`: `options of lint rule "too-many-steps": json: cannot unmarshal string`,
+		`{"rules": {"too-many-steps": {"options": {"maximum": 5}}}}`:  `options of lint rule "too-many-steps": json: unknown field "maximum"`,
+	} {
+		config, err := lint.ReadConfig(strings.NewReader(content))
+		if assert.NoError(t, err, content) {
+			err = config.Apply(lint.NewLinter())
+			if assert.Error(t, err, content) {
+				assert.Contains(t, err.Error(), expected, content)
+			}
+		}
+	}
+	_, err := lint.ReadConfig(strings.NewReader(`{"rules": [`))
+	assert.Error(t, err)
+}
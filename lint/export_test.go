@@ -0,0 +1,10 @@
+package lint
+
+// SaveRegistered returns a func undoing the calls of Register made since, for
+// tests registering rules.
+func SaveRegistered() (restore func()) {
+	n := len(registered)
+	return func() {
+		registered = registered[:n]
+	}
+}
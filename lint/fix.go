@@ -0,0 +1,75 @@
+package lint
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// Edit replaces the Length bytes of the source at Offset with Text.
+type Edit struct {
+	Offset int
+	Length int
+	Text   string
+}
+
+func (e Edit) end() int {
+	return e.Offset + e.Length
+}
+
+func (e Edit) overlaps(o Edit) bool {
+	return e.Offset == o.Offset || (e.Offset < o.end() && o.Offset < e.end())
+}
+
+type byOffset []Edit
+
+func (e byOffset) Len() int           { return len(e) }
+func (e byOffset) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e byOffset) Less(i, j int) bool { return e[i].Offset < e[j].Offset }
+
+// ApplyFixes applies the edits of diagnostics to source. The fix of a
+// diagnostic is skipped as a whole if any of its edits overlaps an edit of a
+// previous diagnostic; linting the result again brings it up again.
+func ApplyFixes(source string, diagnostics []*Diagnostic) string {
+	var edits []Edit
+	for _, d := range diagnostics {
+		if !overlapsAny(d.Edits, edits) {
+			edits = append(edits, d.Edits...)
+		}
+	}
+	sort.Stable(byOffset(edits))
+	for i := len(edits) - 1; i >= 0; i-- {
+		e := edits[i]
+		source = source[:e.Offset] + e.Text + source[e.end():]
+	}
+	return source
+}
+
+func overlapsAny(edits, accepted []Edit) bool {
+	for _, e := range edits {
+		for _, a := range accepted {
+			if e.overlaps(a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FixFile applies the fixes of linter to the file at path and reports whether
+// the file changed. Unchanged files are not written.
+func FixFile(linter Linter, path string) (bool, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	fixed, err := linter.Fix(string(data))
+	if err != nil || fixed == string(data) {
+		return false, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	return true, ioutil.WriteFile(path, []byte(fixed), info.Mode())
+}
@@ -0,0 +1,291 @@
+package lint
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/formater"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+// The rules of this file come with fixes, see Linter.Fix.
+
+type sourceLine struct {
+	text   string // without line ending
+	offset int    // byte offset of the line
+}
+
+func sourceLines(source string) []sourceLine {
+	var lines []sourceLine
+	offset := 0
+	for _, text := range strings.SplitAfter(source, "\n") {
+		if text == "" {
+			break
+		}
+		lines = append(lines, sourceLine{strings.TrimRight(text, "\r\n"), offset})
+		offset += len(text)
+	}
+	return lines
+}
+
+// position returns the position of the byte index i of the line with number n.
+func (l sourceLine) position(n, i int) events.Position {
+	return events.Position{Offset: l.offset + i, Line: n, Column: utf8.RuneCountInString(l.text[:i]) + 1}
+}
+
+func isBlank(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
+
+// ----------------------------------------
+
+// TrailingWhitespace reports whitespace at the end of lines, except for the
+// content of PyStrings.
+type TrailingWhitespace struct{}
+
+func (*TrailingWhitespace) Name() string { return "trailing-whitespace" }
+
+func (*TrailingWhitespace) Check(feature nodes.FeatureNode, r Reporter) {}
+
+func (*TrailingWhitespace) CheckSource(source string, feature nodes.FeatureNode, r Reporter) {
+	content := pyStringLines(feature)
+	for i, line := range sourceLines(source) {
+		if content[i+1] {
+			continue
+		}
+		trimmed := strings.TrimRight(line.text, " \t")
+		if len(trimmed) < len(line.text) {
+			edit := Edit{Offset: line.offset + len(trimmed), Length: len(line.text) - len(trimmed)}
+			r.ReportFix(line.position(i+1, len(trimmed)), []Edit{edit}, "trailing whitespace")
+		}
+	}
+}
+
+// pyStringLines returns the numbers of the lines within PyStrings.
+func pyStringLines(feature nodes.FeatureNode) map[int]bool {
+	lines := make(map[int]bool)
+	if feature == nil {
+		return lines
+	}
+	for _, scenario := range scenarios(feature) {
+		for _, step := range scenario.Steps() {
+			if p := step.PyString(); p != nil {
+				for n := 1; n <= len(p.Lines()); n++ {
+					lines[p.Position().Line+n] = true
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// RepeatedStepType reports Given, When and Then steps following a step of the
+// same type, which should read And.
+type RepeatedStepType struct{}
+
+func (*RepeatedStepType) Name() string { return "repeated-step-type" }
+
+func (*RepeatedStepType) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	for _, scenario := range scenarios(feature) {
+		var previous string
+		for _, step := range scenario.Steps() {
+			stepType := step.StepType()
+			if stepType == previous && (stepType == "Given" || stepType == "When" || stepType == "Then") {
+				var edits []Edit
+				if step.Position().IsValid() {
+					edits = []Edit{{Offset: step.Position().Offset, Length: len(stepType), Text: "And"}}
+				}
+				r.ReportFix(step.Position(), edits, "repeated %s step, use And", stepType)
+			}
+			previous = stepType
+		}
+	}
+}
+
+// UnsortedTags reports tags that are not sorted or given more than once. Tags
+// spread over several lines are reported without a fix.
+type UnsortedTags struct{}
+
+func (*UnsortedTags) Name() string { return "unsorted-tags" }
+
+func (*UnsortedTags) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	check := func(tags []string, positions []events.Position) {
+		if len(tags) < 2 || len(positions) != len(tags) {
+			return
+		}
+		var sorted []string
+		seen := make(map[string]bool)
+		var duplicate string
+		for _, tag := range tags {
+			if seen[tag] {
+				if duplicate == "" {
+					duplicate = tag
+				}
+				continue
+			}
+			seen[tag] = true
+			sorted = append(sorted, tag)
+		}
+		sort.Strings(sorted)
+
+		var message string
+		if duplicate != "" {
+			message = "duplicate tag @" + duplicate
+		} else if !sort.StringsAreSorted(tags) {
+			message = "tags are not sorted"
+		} else {
+			return
+		}
+		first, last := positions[0], positions[len(positions)-1]
+		var edits []Edit
+		if first.IsValid() && first.Line == last.Line {
+			edits = []Edit{{
+				Offset: first.Offset,
+				Length: last.Offset + 1 + len(tags[len(tags)-1]) - first.Offset,
+				Text:   "@" + strings.Join(sorted, " @"),
+			}}
+		}
+		r.ReportFix(first, edits, "%s", message)
+	}
+	check(feature.Tags(), feature.TagPositions())
+	for _, scenario := range scenarios(feature) {
+		check(scenario.Tags(), scenario.TagPositions())
+	}
+}
+
+// BlankLineBeforeScenario reports scenarios and backgrounds that are not
+// separated from what precedes them by a blank line. Tags and comment lines
+// directly above count as part of the scenario.
+type BlankLineBeforeScenario struct{}
+
+func (*BlankLineBeforeScenario) Name() string { return "blank-line-before-scenario" }
+
+func (*BlankLineBeforeScenario) Check(feature nodes.FeatureNode, r Reporter) {}
+
+func (*BlankLineBeforeScenario) CheckSource(source string, feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	lines := sourceLines(source)
+	for _, scenario := range scenarios(feature) {
+		start := scenario.Position().Line
+		for _, pos := range scenario.TagPositions() {
+			if pos.Line < start {
+				start = pos.Line
+			}
+		}
+		for start > 1 && strings.HasPrefix(strings.TrimSpace(lines[start-2].text), "#") {
+			start--
+		}
+		if start <= 1 || isBlank(lines[start-2].text) {
+			continue
+		}
+		line := lines[start-1]
+		r.ReportFix(line.position(start, 0), []Edit{{Offset: line.offset, Text: "\n"}},
+			"missing blank line before %s", strings.ToLower(scenario.NodeType().String()))
+	}
+}
+
+// TableAlignment reports tables whose pipes are not aligned. The fix pads the
+// cells like the pretty formater does, numbers being aligned to the right.
+type TableAlignment struct{}
+
+func (*TableAlignment) Name() string { return "table-alignment" }
+
+func (*TableAlignment) Check(feature nodes.FeatureNode, r Reporter) {}
+
+func (*TableAlignment) CheckSource(source string, feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	for _, table := range tables(feature) {
+		var edits []Edit
+		for i, row := range alignedRows(table) {
+			start, end, ok := rowSpan(source, table, i)
+			if !ok {
+				edits = nil
+				break
+			}
+			if source[start:end] != row {
+				edits = append(edits, Edit{Offset: start, Length: end - start, Text: row})
+			}
+		}
+		if len(edits) > 0 {
+			r.ReportFix(table.Position(), edits, "table is not aligned")
+		}
+	}
+}
+
+// alignedRows returns the rows of table from the first to the last pipe.
+func alignedRows(table nodes.TableNode) []string {
+	widths := make(map[int]int)
+	for _, row := range table.Rows() {
+		for c, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[c] {
+				widths[c] = n
+			}
+		}
+	}
+	var rows []string
+	for _, row := range table.Rows() {
+		s := "|"
+		for c, cell := range row {
+			padding := strings.Repeat(" ", widths[c]-utf8.RuneCountInString(cell))
+			if formater.IsNumber(cell) {
+				s += " " + padding + cell + " |"
+			} else {
+				s += " " + cell + padding + " |"
+			}
+		}
+		rows = append(rows, s)
+	}
+	return rows
+}
+
+// rowSpan returns the byte offsets of the first pipe of row i of table and
+// behind its last pipe.
+func rowSpan(source string, table nodes.TableNode, i int) (start, end int, ok bool) {
+	row := table.Rows()[i]
+	if len(row) == 0 {
+		return 0, 0, false
+	}
+	rowPos, lastPos := nodes.RowPosition(table, i), nodes.CellPosition(table, i, len(row)-1)
+	if !rowPos.IsValid() || !lastPos.IsValid() {
+		return 0, 0, false
+	}
+	end = lastPos.Offset + len(row[len(row)-1])
+	for end < len(source) && (source[end] == ' ' || source[end] == '\t') {
+		end++
+	}
+	if end >= len(source) || source[end] != '|' {
+		return 0, 0, false
+	}
+	return rowPos.Offset, end + 1, true
+}
+
+func tables(feature nodes.FeatureNode) []nodes.TableNode {
+	var t []nodes.TableNode
+	for _, scenario := range scenarios(feature) {
+		for _, step := range scenario.Steps() {
+			if step.Table() != nil {
+				t = append(t, step.Table())
+			}
+		}
+		if outline, ok := scenario.(nodes.OutlineNode); ok {
+			for _, examples := range outline.AllExamples() {
+				if examples.Table() != nil {
+					t = append(t, examples.Table())
+				}
+			}
+		}
+	}
+	return t
+}
@@ -0,0 +1,128 @@
+package lint_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/lint"
+	"github.com/stretchr/testify/assert"
+)
+
+const unfixedFeature = "Feature: Fixes  \n" +
+	"  Background:\n" +
+	"    Given a calculator\n" +
+	"  @slow @fast @slow\n" +
+	"  Scenario: Adding\n" +
+	"    Given the number 2\t\n" +
+	"    Given the number 10\n" +
+	"    When I add them\n" +
+	"    Then the result is:\n" +
+	"      \"\"\"\n" +
+	"      12  \n" +
+	"      \"\"\"\n" +
+	"    # the table\n" +
+	"  Scenario: Table\n" +
+	"    Given the numbers:\n" +
+	"      | name | value |\n" +
+	"      |two|2|\n" +
+	"      | ten  |10 |\n"
+
+const fixedFeature = "Feature: Fixes\n" +
+	"\n" +
+	"  Background:\n" +
+	"    Given a calculator\n" +
+	"\n" +
+	"  @fast @slow\n" +
+	"  Scenario: Adding\n" +
+	"    Given the number 2\n" +
+	"    And the number 10\n" +
+	"    When I add them\n" +
+	"    Then the result is:\n" +
+	"      \"\"\"\n" +
+	"      12  \n" +
+	"      \"\"\"\n" +
+	"\n" +
+	"    # the table\n" +
+	"  Scenario: Table\n" +
+	"    Given the numbers:\n" +
+	"      | name | value |\n" +
+	"      | two  |     2 |\n" +
+	"      | ten  |    10 |\n"
+
+func TestLintSourceFixes(t *testing.T) {
+	diagnostics, err := lint.NewLinter().LintSource(unfixedFeature)
+	assert.NoError(t, err)
+	var out []string
+	for _, d := range diagnostics {
+		if len(d.Edits) > 0 {
+			out = append(out, d.String())
+		}
+	}
+	assert.Equal(t, []string{
+		"1:15: warning: trailing whitespace (trailing-whitespace)",
+		"2:1: info: missing blank line before background (blank-line-before-scenario)",
+		"4:1: info: missing blank line before scenario (blank-line-before-scenario)",
+		"4:3: info: duplicate tag @slow (unsorted-tags)",
+		"6:23: warning: trailing whitespace (trailing-whitespace)",
+		"7:5: info: repeated Given step, use And (repeated-step-type)",
+		"13:1: info: missing blank line before scenario (blank-line-before-scenario)",
+		"16:7: info: table is not aligned (table-alignment)",
+	}, out)
+}
+
+func TestLintSkipsSourceChecks(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature(unfixedFeature)
+	assert.NoError(t, err)
+	for _, d := range lint.NewLinter().Lint(feature) {
+		assert.NotEqual(t, "table-alignment", d.Rule)
+		assert.NotEqual(t, "trailing-whitespace", d.Rule)
+	}
+}
+
+func TestLinterFix(t *testing.T) {
+	l := lint.NewLinter()
+	fixed, err := l.Fix(unfixedFeature)
+	assert.NoError(t, err)
+	assert.Equal(t, fixedFeature, fixed)
+
+	again, err := l.Fix(fixed)
+	assert.NoError(t, err)
+	assert.Equal(t, fixed, again)
+
+	_, err = l.Fix("Scenario: without feature\n")
+	assert.Error(t, err)
+}
+
+func TestApplyFixesSkipsOverlappingEdits(t *testing.T) {
+	source := "Given Given"
+	fixed := lint.ApplyFixes(source, []*lint.Diagnostic{
+		{Edits: []lint.Edit{{Offset: 0, Length: 5, Text: "And"}}},
+		{Edits: []lint.Edit{{Offset: 2, Length: 5, Text: "x"}, {Offset: 6, Length: 5, Text: "Then"}}},
+		{Edits: []lint.Edit{{Offset: 6, Length: 5, Text: "When"}}},
+	})
+	assert.Equal(t, "And When", fixed)
+}
+
+func TestFixFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lint")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "calc.feature")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(unfixedFeature), 0644))
+
+	l := lint.NewLinter()
+	changed, err := lint.FixFile(l, path)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, fixedFeature, string(data))
+
+	changed, err = lint.FixFile(l, path)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
@@ -0,0 +1,259 @@
+// Sub-Package gherkin/lint checks parsed features against a set of rules and
+// reports positioned diagnostics.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "unknown"
+}
+
+// Diagnostic is a single finding of a rule.
+type Diagnostic struct {
+	Rule     string
+	Severity Severity
+	Position events.Position
+	Message  string
+	Edits    []Edit // suggested fix, if any
+}
+
+// String returns the diagnostic in the line:column format most editors and CI
+// systems pick up.
+//
+//	7:3: warning: scenario has no title (missing-title)
+func (d *Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", d.Position, d.Severity, d.Message, d.Rule)
+}
+
+// Rule checks a feature and reports what it finds.
+type Rule interface {
+	Name() string
+	Check(feature nodes.FeatureNode, r Reporter)
+}
+
+// SourceRule is implemented by rules that check the source text as well, for
+// things the parser does not keep, like whitespace. CheckSource is called in
+// addition to Check when linting source. Rules like table-alignment, whose
+// Check does nothing, only report through LintSource and Fix.
+type SourceRule interface {
+	Rule
+	CheckSource(source string, feature nodes.FeatureNode, r Reporter)
+}
+
+// Reporter is handed to rules to report their findings.
+type Reporter interface {
+	Report(position events.Position, format string, args ...interface{})
+	// ReportFix reports a finding along with the edits fixing it.
+	ReportFix(position events.Position, edits []Edit, format string, args ...interface{})
+}
+
+// ----------------------------------------
+
+// Linter runs a set of rules over features.
+//
+//	l := lint.NewLinter()
+//	l.Disable("too-many-steps")
+//	for _, d := range l.Lint(feature) {
+//		fmt.Printf("%s:%s\n", path, d)
+//	}
+type Linter interface {
+	// AddRule adds rule, enabled and reporting with severity. A rule of the
+	// same name is replaced.
+	AddRule(rule Rule, severity Severity)
+	Rule(name string) Rule
+	Rules() []Rule
+	Enable(name string) error
+	Disable(name string) error
+	SetSeverity(name string, severity Severity) error
+	// Lint returns the diagnostics of all enabled rules, ordered by position.
+	// The source is not known, so CheckSource of source rules is not called.
+	Lint(feature nodes.FeatureNode) []*Diagnostic
+	// LintSource parses source and lints it, including the checks of source
+	// rules.
+	LintSource(source string) ([]*Diagnostic, error)
+	// Fix applies the suggested fixes to source until there are none left.
+	Fix(source string) (string, error)
+}
+
+// NewLinter returns a linter with the built-in rules, followed by the rules
+// added with Register.
+func NewLinter() Linter {
+	l := &linter{}
+	for _, d := range builtinRules() {
+		l.AddRule(d.rule, d.severity)
+	}
+	for _, r := range registered {
+		l.AddRule(r.newRule(), r.severity)
+	}
+	return l
+}
+
+type linter struct {
+	rules []*ruleConfig
+}
+
+type ruleConfig struct {
+	rule     Rule
+	severity Severity
+	disabled bool
+}
+
+func (l *linter) AddRule(rule Rule, severity Severity) {
+	c := &ruleConfig{rule: rule, severity: severity}
+	for i, existing := range l.rules {
+		if existing.rule.Name() == rule.Name() {
+			l.rules[i] = c
+			return
+		}
+	}
+	l.rules = append(l.rules, c)
+}
+
+func (l *linter) config(name string) (*ruleConfig, error) {
+	for _, c := range l.rules {
+		if c.rule.Name() == name {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown lint rule %q", name)
+}
+
+func (l *linter) Rule(name string) Rule {
+	if c, err := l.config(name); err == nil {
+		return c.rule
+	}
+	return nil
+}
+
+func (l *linter) Rules() []Rule {
+	rules := make([]Rule, len(l.rules))
+	for i, c := range l.rules {
+		rules[i] = c.rule
+	}
+	return rules
+}
+
+func (l *linter) Enable(name string) error {
+	c, err := l.config(name)
+	if err == nil {
+		c.disabled = false
+	}
+	return err
+}
+
+func (l *linter) Disable(name string) error {
+	c, err := l.config(name)
+	if err == nil {
+		c.disabled = true
+	}
+	return err
+}
+
+func (l *linter) SetSeverity(name string, severity Severity) error {
+	c, err := l.config(name)
+	if err == nil {
+		c.severity = severity
+	}
+	return err
+}
+
+func (l *linter) Lint(feature nodes.FeatureNode) []*Diagnostic {
+	return l.lint(feature, "", false)
+}
+
+func (l *linter) LintSource(source string) ([]*Diagnostic, error) {
+	feature, err := gherkin.ParseGherkinFeature(source)
+	if err != nil {
+		return nil, err
+	}
+	return l.lint(feature, source, true), nil
+}
+
+func (l *linter) lint(feature nodes.FeatureNode, source string, withSource bool) []*Diagnostic {
+	var diagnostics []*Diagnostic
+	for _, c := range l.rules {
+		if c.disabled {
+			continue
+		}
+		r := &ruleReporter{rule: c.rule.Name(), severity: c.severity}
+		c.rule.Check(feature, r)
+		if sr, ok := c.rule.(SourceRule); ok && withSource {
+			sr.CheckSource(source, feature, r)
+		}
+		diagnostics = append(diagnostics, r.diagnostics...)
+	}
+	sort.Stable(byPosition(diagnostics))
+	return diagnostics
+}
+
+// maxFixPasses limits the passes of Fix, in case fixes keep undoing each other.
+const maxFixPasses = 10
+
+func (l *linter) Fix(source string) (string, error) {
+	for i := 0; i < maxFixPasses; i++ {
+		diagnostics, err := l.LintSource(source)
+		if err != nil {
+			return source, err
+		}
+		fixed := ApplyFixes(source, diagnostics)
+		if fixed == source {
+			break
+		}
+		source = fixed
+	}
+	return source, nil
+}
+
+type ruleReporter struct {
+	rule        string
+	severity    Severity
+	diagnostics []*Diagnostic
+}
+
+func (r *ruleReporter) Report(position events.Position, format string, args ...interface{}) {
+	r.ReportFix(position, nil, format, args...)
+}
+
+func (r *ruleReporter) ReportFix(position events.Position, edits []Edit, format string, args ...interface{}) {
+	r.diagnostics = append(r.diagnostics, &Diagnostic{
+		Rule:     r.rule,
+		Severity: r.severity,
+		Position: position,
+		Message:  fmt.Sprintf(format, args...),
+		Edits:    edits,
+	})
+}
+
+type byPosition []*Diagnostic
+
+func (d byPosition) Len() int      { return len(d) }
+func (d byPosition) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d byPosition) Less(i, j int) bool {
+	if d[i].Position.Line != d[j].Position.Line {
+		return d[i].Position.Line < d[j].Position.Line
+	}
+	return d[i].Position.Column < d[j].Position.Column
+}
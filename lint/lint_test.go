@@ -0,0 +1,153 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/lint"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/stretchr/testify/assert"
+)
+
+const messyFeature = `@wip
+Feature: Calculator
+
+  Background:
+    Given a calculator
+
+  Scenario: Adding
+    When I press "2 + 2"
+    Given a fresh calculator
+    Then the result is 4
+
+  Scenario: Adding
+    When I press "2 + 3"
+    Then the result is 5
+
+  Scenario:
+
+  Scenario Outline: Math
+    When I press "<left> + <right>"
+    Then the result is 4
+
+    Examples:
+      | left | right | result |
+      | 2    | 2     | 4      |
+      | 1    | 3     |
+`
+
+func lintStrings(l lint.Linter, content string) []string {
+	feature, _ := gherkin.ParseGherkinFeature(content)
+	var out []string
+	for _, d := range l.Lint(feature) {
+		out = append(out, d.String())
+	}
+	return out
+}
+
+func TestLinter(t *testing.T) {
+	l := lint.NewLinter()
+	l.Rule("disallowed-tags").(*lint.DisallowedTags).Tags = []string{"@wip"}
+
+	assert.Equal(t, []string{
+		"1:1: error: tag @wip is not allowed (disallowed-tags)",
+		"4:3: info: background has a single step (single-step-background)",
+		"9:5: warning: Given step after When step (given-after-when)",
+		`12:3: error: duplicate scenario name "Adding", first used on line 7 (duplicate-scenario-name)`,
+		"16:3: warning: scenario has no title (missing-title)",
+		"16:3: warning: scenario has no steps (empty-scenario)",
+		`23:24: warning: examples column "result" is not used by the outline (unused-placeholder)`,
+		"25:7: error: table row has 2 cells, expected 3 (inconsistent-table)",
+	}, lintStrings(l, messyFeature))
+}
+
+func TestDisallowedTagsWithoutPositions(t *testing.T) {
+	feature := nodes.NewMutableFeatureNode("F", "", []string{"wip"})
+	feature.AddScenario(nodes.NewMutableScenarioNode("S", []string{"ok", "wip"}))
+	l := lint.NewLinter()
+	l.Rule("disallowed-tags").(*lint.DisallowedTags).Tags = []string{"@wip"}
+	var found []string
+	for _, d := range l.Lint(feature) {
+		if d.Rule == "disallowed-tags" {
+			found = append(found, d.Message)
+		}
+	}
+	assert.Equal(t, []string{"tag @wip is not allowed", "tag @wip is not allowed"}, found)
+}
+
+func TestLinterConfiguration(t *testing.T) {
+	l := lint.NewLinter()
+	assert.NoError(t, l.Disable("single-step-background"))
+	assert.NoError(t, l.Disable("given-after-when"))
+	assert.NoError(t, l.SetSeverity("inconsistent-table", lint.SeverityWarning))
+	l.Rule("too-many-steps").(*lint.TooManySteps).Max = 2
+	assert.Error(t, l.Disable("no-such-rule"))
+
+	assert.Equal(t, []string{
+		"7:3: warning: scenario has 3 steps, more than 2 (too-many-steps)",
+		`12:3: error: duplicate scenario name "Adding", first used on line 7 (duplicate-scenario-name)`,
+		"16:3: warning: scenario has no title (missing-title)",
+		"16:3: warning: scenario has no steps (empty-scenario)",
+		`23:24: warning: examples column "result" is not used by the outline (unused-placeholder)`,
+		"25:7: warning: table row has 2 cells, expected 3 (inconsistent-table)",
+	}, lintStrings(l, messyFeature))
+
+	assert.NoError(t, l.Enable("given-after-when"))
+	assert.Contains(t, lintStrings(l, messyFeature), "9:5: warning: Given step after When step (given-after-when)")
+}
+
+func TestLinterNoScenarios(t *testing.T) {
+	l := lint.NewLinter()
+	assert.Equal(t, []string{
+		"1:1: warning: feature has no scenarios (no-scenarios)",
+	}, lintStrings(l, "Feature: Nothing yet\n"))
+	assert.Equal(t, []string{
+		"1:1: warning: file has no feature (no-scenarios)",
+	}, lintStrings(l, "# just a comment\n"))
+}
+
+func TestLeadingConjunction(t *testing.T) {
+	content := `Feature: F
+  Background:
+    And a calculator
+    Given it is on
+
+  Scenario: Continues the background
+    And it is on
+    When I press "2"
+
+  Scenario Outline: Starts with a star
+    * I press "<key>"
+    Then the result is 4
+
+    Examples:
+      | key |
+`
+	l := lint.NewLinter()
+	assert.Equal(t, []string{
+		"3:5: warning: background starts with And step (leading-conjunction)",
+	}, lintStrings(l, content))
+
+	l.Rule("leading-conjunction").(*lint.LeadingConjunction).Strict = true
+	assert.Equal(t, []string{
+		"3:5: warning: background starts with And step (leading-conjunction)",
+		"7:5: warning: scenario starts with And step (leading-conjunction)",
+		"11:5: warning: outline starts with * step (leading-conjunction)",
+	}, lintStrings(l, content))
+}
+
+func TestUnknownPlaceholder(t *testing.T) {
+	l := lint.NewLinter()
+	assert.Equal(t, []string{
+		"3:18: error: placeholder <Balanse> is not a column of the examples on line 6 (unknown-placeholder)",
+		`7:9: warning: examples column "Balance" is not used by the outline (unused-placeholder)`,
+	}, lintStrings(l, `Feature: F
+  Scenario Outline: O
+    Given I have <Balanse>
+    When I withdraw everything
+
+    Examples:
+      | Balance |
+      | $50     |
+`))
+}
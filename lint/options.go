@@ -0,0 +1,17 @@
+//go:build go1.10
+// +build go1.10
+
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// decodeOptions sets the options of a rule that is not Configurable, which
+// must not have keys the rule does not know.
+func decodeOptions(options json.RawMessage, rule Rule) error {
+	d := json.NewDecoder(bytes.NewReader(options))
+	d.DisallowUnknownFields()
+	return d.Decode(rule)
+}
@@ -0,0 +1,37 @@
+//go:build !go1.10
+// +build !go1.10
+
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// decodeOptions sets the options of a rule that is not Configurable, which
+// must not have keys the rule does not know. json.Decoder can not reject them
+// before Go 1.10, so they are compared with the fields of the rule.
+func decodeOptions(options json.RawMessage, rule Rule) error {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(options, &keys); err == nil {
+		known := make(map[string]bool)
+		if t := reflect.TypeOf(rule); t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+			for i := 0; i < t.Elem().NumField(); i++ {
+				field := t.Elem().Field(i)
+				name := strings.Split(field.Tag.Get("json"), ",")[0]
+				if name == "" {
+					name = field.Name
+				}
+				known[strings.ToLower(name)] = true
+			}
+		}
+		for key := range keys {
+			if !known[strings.ToLower(key)] {
+				return fmt.Errorf("json: unknown field %q", key)
+			}
+		}
+	}
+	return json.Unmarshal(options, rule)
+}
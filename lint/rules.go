@@ -0,0 +1,306 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/pickles"
+)
+
+type builtinRule struct {
+	rule     Rule
+	severity Severity
+}
+
+func builtinRules() []builtinRule {
+	return []builtinRule{
+		{&NoScenarios{}, SeverityWarning},
+		{&MissingTitle{}, SeverityWarning},
+		{&DuplicateScenarioName{}, SeverityError},
+		{&EmptyScenario{}, SeverityWarning},
+		{&SingleStepBackground{}, SeverityInfo},
+		{&GivenAfterWhen{}, SeverityWarning},
+		{&LeadingConjunction{}, SeverityWarning},
+		{&TooManySteps{Max: TooManyStepsDefault}, SeverityWarning},
+		{&UnusedPlaceholder{}, SeverityWarning},
+		{&UnknownPlaceholder{}, SeverityError},
+		{&InconsistentTable{}, SeverityError},
+		{&DisallowedTags{}, SeverityError},
+		{&TrailingWhitespace{}, SeverityWarning},
+		{&RepeatedStepType{}, SeverityInfo},
+		{&UnsortedTags{}, SeverityInfo},
+		{&BlankLineBeforeScenario{}, SeverityInfo},
+		{&TableAlignment{}, SeverityInfo},
+	}
+}
+
+// scenarios returns the background, if any, followed by the scenarios.
+func scenarios(feature nodes.FeatureNode) []nodes.ScenarioNode {
+	var s []nodes.ScenarioNode
+	if feature.Background() != nil {
+		s = append(s, feature.Background())
+	}
+	return append(s, feature.Scenarios()...)
+}
+
+// ----------------------------------------
+
+// NoScenarios reports files without any scenario.
+type NoScenarios struct{}
+
+func (*NoScenarios) Name() string { return "no-scenarios" }
+
+func (*NoScenarios) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		r.Report(events.Position{Line: 1, Column: 1}, "file has no feature")
+	} else if len(feature.Scenarios()) == 0 {
+		r.Report(feature.Position(), "feature has no scenarios")
+	}
+}
+
+// MissingTitle reports features and scenarios without a title.
+type MissingTitle struct{}
+
+func (*MissingTitle) Name() string { return "missing-title" }
+
+func (*MissingTitle) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	if feature.Title() == "" {
+		r.Report(feature.Position(), "feature has no title")
+	}
+	for _, scenario := range feature.Scenarios() {
+		if scenario.Title() == "" {
+			r.Report(scenario.Position(), "scenario has no title")
+		}
+	}
+}
+
+// DuplicateScenarioName reports scenarios with the title of a previous one.
+type DuplicateScenarioName struct{}
+
+func (*DuplicateScenarioName) Name() string { return "duplicate-scenario-name" }
+
+func (*DuplicateScenarioName) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	seen := make(map[string]events.Position)
+	for _, scenario := range feature.Scenarios() {
+		title := scenario.Title()
+		if title == "" {
+			continue
+		}
+		if first, ok := seen[title]; ok {
+			r.Report(scenario.Position(), "duplicate scenario name %q, first used on line %d", title, first.Line)
+		} else {
+			seen[title] = scenario.Position()
+		}
+	}
+}
+
+// EmptyScenario reports scenarios without steps.
+type EmptyScenario struct{}
+
+func (*EmptyScenario) Name() string { return "empty-scenario" }
+
+func (*EmptyScenario) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	for _, scenario := range feature.Scenarios() {
+		if len(scenario.Steps()) == 0 {
+			r.Report(scenario.Position(), "scenario has no steps")
+		}
+	}
+}
+
+// SingleStepBackground reports backgrounds with just one step, which reads
+// better as part of the scenarios.
+type SingleStepBackground struct{}
+
+func (*SingleStepBackground) Name() string { return "single-step-background" }
+
+func (*SingleStepBackground) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil || feature.Background() == nil {
+		return
+	}
+	if background := feature.Background(); len(background.Steps()) == 1 {
+		r.Report(background.Position(), "background has a single step")
+	}
+}
+
+// GivenAfterWhen reports Given steps following a When or Then step of the same
+// scenario. And and But steps count as the step type they continue.
+type GivenAfterWhen struct{}
+
+func (*GivenAfterWhen) Name() string { return "given-after-when" }
+
+func (*GivenAfterWhen) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	for _, scenario := range feature.Scenarios() {
+		action := nodes.UnknownKeywordType
+		for _, step := range scenario.Steps() {
+			switch kt := step.KeywordType(); kt {
+			case nodes.ActionKeywordType, nodes.OutcomeKeywordType:
+				if action == nodes.UnknownKeywordType {
+					action = kt
+				}
+			case nodes.ContextKeywordType:
+				if action != nodes.UnknownKeywordType {
+					r.Report(step.Position(), "Given step after %s step", keywords[action])
+				}
+			}
+		}
+	}
+}
+
+var keywords = map[nodes.KeywordType]string{
+	nodes.ContextKeywordType: "Given",
+	nodes.ActionKeywordType:  "When",
+	nodes.OutcomeKeywordType: "Then",
+}
+
+// LeadingConjunction reports And, But, Or and * steps that do not follow any
+// step, so that their type is unknown. In Strict mode, all backgrounds and
+// scenarios starting with one are reported, even if they continue the steps
+// of the background.
+type LeadingConjunction struct {
+	Strict bool `json:"strict"`
+}
+
+func (*LeadingConjunction) Name() string { return "leading-conjunction" }
+
+func (rule *LeadingConjunction) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	for _, scenario := range scenarios(feature) {
+		steps := scenario.Steps()
+		if len(steps) == 0 || nodes.StepKeywordType(steps[0].StepType()) != nodes.UnknownKeywordType {
+			continue
+		}
+		if rule.Strict || steps[0].KeywordType() == nodes.UnknownKeywordType {
+			r.Report(steps[0].Position(), "%s starts with %s step", strings.ToLower(scenario.NodeType().String()), steps[0].StepType())
+		}
+	}
+}
+
+const TooManyStepsDefault = 10
+
+// TooManySteps reports scenarios with more than Max steps.
+type TooManySteps struct {
+	Max int `json:"max"`
+}
+
+func (*TooManySteps) Name() string { return "too-many-steps" }
+
+func (rule *TooManySteps) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil || rule.Max <= 0 {
+		return
+	}
+	for _, scenario := range scenarios(feature) {
+		if n := len(scenario.Steps()); n > rule.Max {
+			r.Report(scenario.Position(), "%s has %d steps, more than %d", strings.ToLower(scenario.NodeType().String()), n, rule.Max)
+		}
+	}
+}
+
+// UnusedPlaceholder reports columns of examples that are not used as
+// <placeholder> by their scenario outline.
+type UnusedPlaceholder struct{}
+
+func (*UnusedPlaceholder) Name() string { return "unused-placeholder" }
+
+func (*UnusedPlaceholder) Check(feature nodes.FeatureNode, r Reporter) {
+	for _, outline := range outlines(feature) {
+		for _, column := range pickles.CheckOutline(outline).Unused {
+			r.Report(column.Pos, "examples column %q is not used by the outline", column.Name)
+		}
+	}
+}
+
+// UnknownPlaceholder reports <placeholders> of scenario outlines that are not
+// a column of their examples, and stay as they are in the pickles.
+type UnknownPlaceholder struct{}
+
+func (*UnknownPlaceholder) Name() string { return "unknown-placeholder" }
+
+func (*UnknownPlaceholder) Check(feature nodes.FeatureNode, r Reporter) {
+	for _, outline := range outlines(feature) {
+		for _, p := range pickles.CheckOutline(outline).Unknown {
+			r.Report(p.Pos, "placeholder <%s> is not a column of the examples on line %d", p.Name, p.Examples.Position().Line)
+		}
+	}
+}
+
+func outlines(feature nodes.FeatureNode) []nodes.OutlineNode {
+	if feature == nil {
+		return nil
+	}
+	var result []nodes.OutlineNode
+	for _, scenario := range feature.Scenarios() {
+		if outline, ok := scenario.(nodes.OutlineNode); ok {
+			result = append(result, outline)
+		}
+	}
+	return result
+}
+
+// InconsistentTable reports table rows with a different number of cells than
+// the first row.
+type InconsistentTable struct{}
+
+func (*InconsistentTable) Name() string { return "inconsistent-table" }
+
+func (*InconsistentTable) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil {
+		return
+	}
+	for _, table := range tables(feature) {
+		rows := table.Rows()
+		for i := 1; i < len(rows); i++ {
+			if len(rows[i]) != len(rows[0]) {
+				r.Report(nodes.RowPosition(table, i), "table row has %d cells, expected %d", len(rows[i]), len(rows[0]))
+			}
+		}
+	}
+}
+
+// DisallowedTags reports the use of any of Tags, given with or without the
+// leading '@'.
+type DisallowedTags struct {
+	Tags []string `json:"tags"`
+}
+
+func (*DisallowedTags) Name() string { return "disallowed-tags" }
+
+func (rule *DisallowedTags) Check(feature nodes.FeatureNode, r Reporter) {
+	if feature == nil || len(rule.Tags) == 0 {
+		return
+	}
+	disallowed := make(map[string]bool)
+	for _, tag := range rule.Tags {
+		disallowed[strings.TrimPrefix(tag, "@")] = true
+	}
+	check := func(tags []string, positions []events.Position) {
+		for i, tag := range tags {
+			if !disallowed[tag] {
+				continue
+			}
+			var pos events.Position // unknown for nodes not created by the parser
+			if i < len(positions) {
+				pos = positions[i]
+			}
+			r.Report(pos, "tag @%s is not allowed", tag)
+		}
+	}
+	check(feature.Tags(), feature.TagPositions())
+	for _, scenario := range scenarios(feature) {
+		check(scenario.Tags(), scenario.TagPositions())
+	}
+}
@@ -0,0 +1,63 @@
+//go:build go1.16
+// +build go1.16
+
+package loader
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+func checkGlob(pattern string) error {
+	if _, err := path.Match(strings.Replace(pattern, "**", "*", -1), ""); err != nil {
+		return fmt.Errorf("glob %q: %s", pattern, err)
+	}
+	return nil
+}
+
+func matchAny(patterns []string, name string, isDir bool) bool {
+	for _, pattern := range patterns {
+		if match(pattern, name, isDir) {
+			return true
+		}
+	}
+	return false
+}
+
+// match reports whether the slash-separated name matches the glob pattern,
+// see Loader.
+func match(pattern, name string, isDir bool) bool {
+	if strings.HasSuffix(pattern, "/") {
+		if !isDir {
+			return false
+		}
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return matchSegments(strings.Split(strings.TrimPrefix(pattern, "/"), "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,171 @@
+//go:build go1.16
+// +build go1.16
+
+// Sub-Package gherkin/loader parses the feature files of a directory tree,
+// given as fs.FS, concurrently. It needs Go 1.16 or later for io/fs.
+package loader
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+// DefaultInclude are the files loaded when Loader.Include is empty.
+var DefaultInclude = []string{"*.feature", "*.feature.md"}
+
+// Loader loads the feature files of an fs.FS. Hidden directories are skipped.
+//
+// The globs are slash-separated and relative to the root, with "**" matching
+// any number of directories. Globs without a slash match the base name of a
+// file or directory at any depth, globs with a trailing slash only match
+// directories. Files with the name IgnoreFile, like ".gherkinignore", hold
+// further Exclude globs, one per line, relative to their directory. Blank
+// lines and lines starting with "#" are skipped.
+type Loader struct {
+	Include    []string // globs of the files to load, DefaultInclude if empty
+	Exclude    []string // globs of the files and directories to skip
+	IgnoreFile string   // name of the ignore files, none if empty
+	Workers    int      // number of files parsed concurrently, GOMAXPROCS if 0
+}
+
+// File is a loaded feature file. Files ending with .feature.md are parsed as
+// Markdown with Gherkin.
+type File struct {
+	Path    string            // slash-separated, relative to the root of the fs.FS
+	Feature nodes.FeatureNode // nil if Err is set
+	Err     error             // of reading or parsing the file, or gherkin.ErrNoFeature
+}
+
+// Load returns the feature files of fsys in lexical order of their paths. The
+// errors of single files and directories do not stop the load, they are
+// returned as File with Err set. The error returned is that of a malformed
+// glob.
+func (l *Loader) Load(fsys fs.FS) ([]*File, error) {
+	include := l.Include
+	if len(include) == 0 {
+		include = DefaultInclude
+	}
+	for _, pattern := range append(append([]string{}, include...), l.Exclude...) {
+		if err := checkGlob(pattern); err != nil {
+			return nil, err
+		}
+	}
+	w := &walker{loader: l, fsys: fsys, include: include, ignores: map[string][]string{}}
+	fs.WalkDir(fsys, ".", w.visit)
+	sort.SliceStable(w.files, func(i, j int) bool { return w.files[i].Path < w.files[j].Path })
+	w.parse()
+	return w.files, nil
+}
+
+type walker struct {
+	loader  *Loader
+	fsys    fs.FS
+	include []string
+	ignores map[string][]string // globs of the ignore file of each directory
+	files   []*File
+}
+
+func (w *walker) visit(p string, d fs.DirEntry, err error) error {
+	if err != nil {
+		w.files = append(w.files, &File{Path: p, Err: err})
+		return nil
+	}
+	if d.IsDir() {
+		if p != "." && (strings.HasPrefix(d.Name(), ".") || w.excluded(p, true)) {
+			return fs.SkipDir
+		}
+		w.readIgnoreFile(p)
+		return nil
+	}
+	if !w.excluded(p, false) && matchAny(w.include, p, false) {
+		w.files = append(w.files, &File{Path: p})
+	}
+	return nil
+}
+
+func (w *walker) readIgnoreFile(dir string) {
+	if w.loader.IgnoreFile == "" {
+		return
+	}
+	name := path.Join(dir, w.loader.IgnoreFile)
+	data, err := fs.ReadFile(w.fsys, name)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			w.files = append(w.files, &File{Path: name, Err: err})
+		}
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := checkGlob(line); err != nil {
+			w.files = append(w.files, &File{Path: name, Err: err})
+			continue
+		}
+		w.ignores[dir] = append(w.ignores[dir], line)
+	}
+}
+
+// excluded reports whether p is matched by Exclude or an ignore file of one of
+// its parent directories.
+func (w *walker) excluded(p string, isDir bool) bool {
+	if matchAny(w.loader.Exclude, p, isDir) {
+		return true
+	}
+	for dir := path.Dir(p); ; dir = path.Dir(dir) {
+		rel := p
+		if dir != "." {
+			rel = p[len(dir)+1:]
+		}
+		if matchAny(w.ignores[dir], rel, isDir) {
+			return true
+		}
+		if dir == "." {
+			return false
+		}
+	}
+}
+
+// parse reads and parses the files, with a bounded number of workers.
+func (w *walker) parse() {
+	workers := w.loader.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	jobs := make(chan *File)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				f.Feature, f.Err = parseFile(w.fsys, f.Path)
+			}
+		}()
+	}
+	for _, f := range w.files {
+		if f.Err == nil {
+			jobs <- f
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func parseFile(fsys fs.FS, name string) (nodes.FeatureNode, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return gherkin.ParseFeatureFile(name, string(data))
+}
@@ -0,0 +1,116 @@
+//go:build go1.16
+// +build go1.16
+
+package loader_test
+
+import (
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/loader"
+	"github.com/stretchr/testify/assert"
+)
+
+func feature(title string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte("Feature: " + title + "\n  Scenario: S\n    Given a step\n")}
+}
+
+var testFS = fstest.MapFS{
+	"login.feature":                  feature("Login"),
+	"README.md":                      &fstest.MapFile{Data: []byte("# Features\n")},
+	"account/withdraw.feature":       feature("Withdraw"),
+	"account/deposit.feature":        feature("Deposit"),
+	"account/broken.feature":         &fstest.MapFile{Data: []byte("Scenario: without feature\n")},
+	"account/.gherkinignore":         &fstest.MapFile{Data: []byte("# slow ones\nslow/\nlegacy-*.feature\n")},
+	"account/legacy-1.feature":       feature("Legacy"),
+	"account/slow/big.feature":       feature("Big"),
+	"docs/guide.feature.md":          &fstest.MapFile{Data: []byte("# Feature: Guide\n## Scenario: S\n* Given a step\n")},
+	"docs/drafts/draft.feature":      feature("Draft"),
+	".git/hooks/hidden.feature":      feature("Hidden"),
+	"vendor/x/features/dep.feature":  feature("Vendored"),
+	"account/slow/nested/ok.feature": feature("Ignored too"),
+}
+
+func ExampleLoader() {
+	l := &loader.Loader{Exclude: []string{"vendor/", "docs/drafts/**"}, IgnoreFile: ".gherkinignore", Workers: 2}
+	files, _ := l.Load(testFS)
+	for _, f := range files {
+		if f.Err != nil {
+			fmt.Printf("%s: error\n", f.Path)
+		} else {
+			fmt.Printf("%s: %s\n", f.Path, f.Feature.Title())
+		}
+	}
+
+	// Output:
+	// account/broken.feature: error
+	// account/deposit.feature: Deposit
+	// account/withdraw.feature: Withdraw
+	// docs/guide.feature.md: Guide
+	// login.feature: Login
+}
+
+func TestLoaderInclude(t *testing.T) {
+	l := &loader.Loader{Include: []string{"account/**/*.feature"}, Exclude: []string{"broken.feature"}}
+	files, err := l.Load(testFS)
+	assert.NoError(t, err)
+	var paths []string
+	for _, f := range files {
+		assert.NoError(t, f.Err)
+		paths = append(paths, f.Path)
+	}
+	assert.Equal(t, []string{
+		"account/deposit.feature",
+		"account/legacy-1.feature",
+		"account/slow/big.feature",
+		"account/slow/nested/ok.feature",
+		"account/withdraw.feature",
+	}, paths)
+}
+
+func TestLoaderErrors(t *testing.T) {
+	_, err := (&loader.Loader{Exclude: []string{"[a-"}}).Load(testFS)
+	assert.Error(t, err)
+
+	files, err := (&loader.Loader{IgnoreFile: "ignore"}).Load(fstest.MapFS{
+		"ignore":    &fstest.MapFile{Data: []byte("[a-\n")},
+		"a.feature": feature("A"),
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, files, 2) {
+		assert.Equal(t, "a.feature", files[0].Path)
+		assert.NoError(t, files[0].Err)
+		assert.Equal(t, "ignore", files[1].Path)
+		assert.Error(t, files[1].Err)
+	}
+}
+
+func TestLoaderEmptyFiles(t *testing.T) {
+	files, err := (&loader.Loader{}).Load(fstest.MapFS{
+		"empty.feature":    &fstest.MapFile{},
+		"empty.feature.md": &fstest.MapFile{Data: []byte("\n")},
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, files, 2) {
+		for _, f := range files {
+			assert.Equal(t, gherkin.ErrNoFeature, f.Err, f.Path)
+			assert.Nil(t, f.Feature, f.Path)
+		}
+	}
+}
+
+func TestLoaderManyFiles(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for i := 0; i < 100; i++ {
+		fsys[fmt.Sprintf("f/%03d.feature", i)] = feature(fmt.Sprint(i))
+	}
+	files, err := (&loader.Loader{Workers: 8}).Load(fsys)
+	assert.NoError(t, err)
+	if assert.Len(t, files, 100) {
+		for i, f := range files {
+			assert.Equal(t, fmt.Sprint(i), f.Feature.Title())
+		}
+	}
+}
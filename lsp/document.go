@@ -0,0 +1,115 @@
+package lsp
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+// document is an open text document along with the result of parsing it.
+type document struct {
+	uri     string
+	text    string
+	lines   []string          // without line endings
+	feature nodes.FeatureNode // nil if err is set
+	err     error
+}
+
+func newDocument(uri, text string) *document {
+	d := &document{uri: uri, text: text, lines: strings.Split(text, "\n")}
+	for i, line := range d.lines {
+		d.lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	d.feature, d.err = gherkin.ParseFeatureFile(uri, text)
+	return d
+}
+
+// uriToPath returns the file system path of a file URI, or uri unchanged if
+// it is none.
+func uriToPath(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		return u.Path
+	}
+	return uri
+}
+
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// utf16Len returns the length of s in UTF-16 code units, the unit of LSP
+// character offsets.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+func (d *document) line(n int) string {
+	if n < 0 || n >= len(d.lines) {
+		return ""
+	}
+	return d.lines[n]
+}
+
+// position converts the position of a node to an LSP position.
+func (d *document) position(p events.Position) Position {
+	if !p.IsValid() {
+		return Position{}
+	}
+	line := d.line(p.Line - 1)
+	i := 0
+	for column := 1; column < p.Column && i < len(line); column++ {
+		_, size := utf8.DecodeRuneInString(line[i:])
+		i += size
+	}
+	return Position{Line: p.Line - 1, Character: utf16Len(line[:i])}
+}
+
+// lineEnd returns the position at the end of line n, trailing whitespace
+// excluded.
+func (d *document) lineEnd(n int) Position {
+	return Position{Line: n, Character: utf16Len(strings.TrimRight(d.line(n), " \t"))}
+}
+
+// rangeToLineEnd returns the range from p to the end of its line.
+func (d *document) rangeToLineEnd(p events.Position) Range {
+	start := d.position(p)
+	end := d.lineEnd(start.Line)
+	if end.Character < start.Character {
+		end = start
+	}
+	return Range{start, end}
+}
+
+func (d *document) fullRange() Range {
+	last := len(d.lines) - 1
+	return Range{End: Position{Line: last, Character: utf16Len(d.lines[last])}}
+}
+
+// prefix returns the text of the line of pos in front of pos.
+func (d *document) prefix(pos Position) string {
+	line := d.line(pos.Line)
+	n := 0
+	for i, r := range line {
+		if n >= pos.Character {
+			return line[:i]
+		}
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return line
+}
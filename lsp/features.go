@@ -0,0 +1,250 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/formater"
+	"github.com/muhqu/go-gherkin/lint"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+func (s *server) diagnostics(doc *document) []*Diagnostic {
+	diagnostics := []*Diagnostic{}
+	// the lint rules report missing features
+	if doc.err != nil && doc.err != gherkin.ErrNoFeature {
+		d := &Diagnostic{Severity: SeverityError, Source: "gherkin", Message: doc.err.Error()}
+		if e, ok := doc.err.(*gherkin.ParseError); ok {
+			d.Range = doc.rangeToLineEnd(e.Position())
+			d.Message = e.Msg
+		}
+		return append(diagnostics, d)
+	}
+
+	var found []*lint.Diagnostic
+	if gherkin.IsMarkdownFile(doc.uri) {
+		found = s.linter.Lint(doc.feature)
+	} else {
+		found, _ = s.linter.LintSource(doc.text)
+	}
+	for _, d := range found {
+		severity := SeverityInformation
+		switch d.Severity {
+		case lint.SeverityError:
+			severity = SeverityError
+		case lint.SeverityWarning:
+			severity = SeverityWarning
+		}
+		diagnostics = append(diagnostics, &Diagnostic{
+			Range:    doc.rangeToLineEnd(d.Position),
+			Severity: severity,
+			Code:     d.Rule,
+			Source:   "gherkin-lint",
+			Message:  d.Message,
+		})
+	}
+	return diagnostics
+}
+
+// formatting replaces the whole document with the output of the pretty
+// formater. Documents that do not parse and Markdown are left alone.
+func (s *server) formatting(params json.RawMessage) (interface{}, error) {
+	doc, err := s.document(params)
+	if err != nil || doc == nil || doc.feature == nil || gherkin.IsMarkdownFile(doc.uri) {
+		return []*TextEdit{}, err
+	}
+	var buf bytes.Buffer
+	(&formater.GherkinPrettyFormater{}).FormatFeature(doc.feature, &buf)
+	if buf.String() == doc.text {
+		return []*TextEdit{}, nil
+	}
+	return []*TextEdit{{Range: doc.fullRange(), NewText: buf.String()}}, nil
+}
+
+// ----------------------------------------
+
+func symbolName(keyword, title string) string {
+	if title == "" {
+		return keyword
+	}
+	return keyword + ": " + title
+}
+
+// lastLine returns the number of the last line of scenario, its steps and
+// examples included. The parser adds the nodes in document order.
+func lastLine(scenario nodes.ScenarioNode) int {
+	last := scenario.Position().Line
+	if steps := scenario.Steps(); len(steps) > 0 {
+		last = stepLastLine(steps[len(steps)-1])
+	}
+	if outline, ok := scenario.(nodes.OutlineNode); ok {
+		if all := outline.AllExamples(); len(all) > 0 {
+			last = examplesLastLine(all[len(all)-1])
+		}
+	}
+	return last
+}
+
+func stepLastLine(step nodes.StepNode) int {
+	if table := step.Table(); table != nil {
+		return tableLastLine(table)
+	}
+	if p := step.PyString(); p != nil {
+		return p.Position().Line + len(p.Lines()) + 1
+	}
+	return step.Position().Line
+}
+
+func examplesLastLine(examples nodes.OutlineExamplesNode) int {
+	if table := examples.Table(); table != nil {
+		return tableLastLine(table)
+	}
+	return examples.Position().Line
+}
+
+func tableLastLine(table nodes.TableNode) int {
+	if rows := table.RowPositions(); len(rows) > 0 {
+		return rows[len(rows)-1].Line
+	}
+	return table.Position().Line
+}
+
+// symbol returns a symbol spanning from the line of pos to line last.
+func (doc *document) symbol(name string, kind SymbolKind, pos events.Position, last int) *DocumentSymbol {
+	start := doc.position(pos)
+	return &DocumentSymbol{
+		Name:           name,
+		Kind:           kind,
+		Range:          Range{Position{Line: start.Line}, doc.lineEnd(last - 1)},
+		SelectionRange: doc.rangeToLineEnd(pos),
+	}
+}
+
+// documentSymbol returns the outline of the document: the feature containing
+// the background, scenarios and outlines, which contain their examples.
+// Gherkin's Rule keyword is not supported by the parser, so there are no
+// rules.
+func (s *server) documentSymbol(params json.RawMessage) (interface{}, error) {
+	symbols := []*DocumentSymbol{}
+	doc, err := s.document(params)
+	if err != nil || doc == nil || doc.feature == nil {
+		return symbols, err
+	}
+	feature := doc.feature
+	f := doc.symbol(symbolName("Feature", feature.Title()), SymbolKindClass, feature.Position(), len(doc.lines))
+	for _, scenario := range scenarios(feature) {
+		sc := doc.symbol(symbolName(formater.ScenarioKeyword(scenario), scenario.Title()), SymbolKindMethod, scenario.Position(), lastLine(scenario))
+		if outline, ok := scenario.(nodes.OutlineNode); ok {
+			for _, examples := range outline.AllExamples() {
+				e := doc.symbol(symbolName("Examples", examples.Title()), SymbolKindArray, examples.Position(), examplesLastLine(examples))
+				sc.Children = append(sc.Children, e)
+			}
+		}
+		f.Children = append(f.Children, sc)
+	}
+	return append(symbols, f), nil
+}
+
+func scenarios(feature nodes.FeatureNode) []nodes.ScenarioNode {
+	var s []nodes.ScenarioNode
+	if feature.Background() != nil {
+		s = append(s, feature.Background())
+	}
+	return append(s, feature.Scenarios()...)
+}
+
+// foldingRange returns ranges for scenarios, examples, tables and PyStrings.
+func (s *server) foldingRange(params json.RawMessage) (interface{}, error) {
+	ranges := []*FoldingRange{}
+	doc, err := s.document(params)
+	if err != nil || doc == nil || doc.feature == nil {
+		return ranges, err
+	}
+	add := func(first, last int) {
+		if last > first {
+			ranges = append(ranges, &FoldingRange{StartLine: first - 1, EndLine: last - 1})
+		}
+	}
+	for _, scenario := range scenarios(doc.feature) {
+		add(scenario.Position().Line, lastLine(scenario))
+		for _, step := range scenario.Steps() {
+			if table := step.Table(); table != nil {
+				add(table.Position().Line, tableLastLine(table))
+			}
+			if p := step.PyString(); p != nil {
+				add(p.Position().Line, p.Position().Line+len(p.Lines())+1)
+			}
+		}
+		if outline, ok := scenario.(nodes.OutlineNode); ok {
+			for _, examples := range outline.AllExamples() {
+				add(examples.Position().Line, examplesLastLine(examples))
+			}
+		}
+	}
+	return ranges, nil
+}
+
+// ----------------------------------------
+
+var (
+	keywords = []string{"Feature:", "Background:", "Scenario:", "Scenario Outline:", "Examples:",
+		"Given ", "When ", "Then ", "And ", "But "}
+	stepPrefix = regexp.MustCompile(`^[ \t]*(?:[*+-][ \t]+)?(Given|When|Then|And|Or|But)[ \t]+`)
+)
+
+// completion completes tags at words starting with '@', step texts after step
+// keywords and keywords at the start of a line.
+func (s *server) completion(params json.RawMessage) (interface{}, error) {
+	p := &TextDocumentPositionParams{}
+	if err := unmarshalParams(params, p); err != nil {
+		return nil, err
+	}
+	list := &CompletionList{Items: []*CompletionItem{}}
+	doc := s.documents[p.TextDocument.URI]
+	if doc == nil {
+		return list, nil
+	}
+	prefix := doc.prefix(p.Position)
+	word := prefix[strings.LastIndexAny(prefix, " \t")+1:]
+	replace := func(from string) Range {
+		return Range{Position{p.Position.Line, utf16Len(prefix) - utf16Len(from)}, p.Position}
+	}
+
+	switch {
+	case strings.HasPrefix(word, "@"):
+		r := replace(word)
+		for _, tag := range s.workspace.tags() {
+			list.Items = append(list.Items, &CompletionItem{
+				Label:    "@" + tag,
+				Kind:     CompletionItemKindValue,
+				TextEdit: &TextEdit{r, "@" + tag},
+			})
+		}
+
+	case stepPrefix.MatchString(prefix):
+		text := prefix[len(stepPrefix.FindString(prefix)):]
+		r := replace(text)
+		for _, step := range s.workspace.steps() {
+			list.Items = append(list.Items, &CompletionItem{
+				Label:    step,
+				Kind:     CompletionItemKindText,
+				TextEdit: &TextEdit{r, step},
+			})
+		}
+
+	case strings.TrimLeft(prefix, " \t") == word:
+		r := replace(word)
+		for _, keyword := range keywords {
+			list.Items = append(list.Items, &CompletionItem{
+				Label:    strings.TrimSpace(keyword),
+				Kind:     CompletionItemKindKeyword,
+				TextEdit: &TextEdit{r, keyword},
+			})
+		}
+	}
+	return list, nil
+}
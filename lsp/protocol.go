@@ -0,0 +1,210 @@
+package lsp
+
+import "encoding/json"
+
+// The subset of the Language Server Protocol types used by the server.
+
+type Position struct {
+	Line      int `json:"line"`      // zero based
+	Character int `json:"character"` // zero based, in UTF-16 code units
+}
+
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+type WorkspaceFolder struct {
+	URI  string `json:"uri"`
+	Name string `json:"name"`
+}
+
+type InitializeParams struct {
+	RootURI          string             `json:"rootUri"`
+	WorkspaceFolders []*WorkspaceFolder `json:"workspaceFolders"`
+}
+
+type InitializeResult struct {
+	Capabilities ServerCapabilities `json:"capabilities"`
+	ServerInfo   *ServerInfo        `json:"serverInfo,omitempty"`
+}
+
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+const TextDocumentSyncFull = 1
+
+type ServerCapabilities struct {
+	TextDocumentSync           int                    `json:"textDocumentSync"`
+	DocumentFormattingProvider bool                   `json:"documentFormattingProvider"`
+	DocumentSymbolProvider     bool                   `json:"documentSymbolProvider"`
+	FoldingRangeProvider       bool                   `json:"foldingRangeProvider"`
+	CompletionProvider         *CompletionOptions     `json:"completionProvider,omitempty"`
+	SemanticTokensProvider     *SemanticTokensOptions `json:"semanticTokensProvider,omitempty"`
+}
+
+type CompletionOptions struct {
+	TriggerCharacters []string `json:"triggerCharacters,omitempty"`
+}
+
+type SemanticTokensLegend struct {
+	TokenTypes     []string `json:"tokenTypes"`
+	TokenModifiers []string `json:"tokenModifiers"`
+}
+
+type SemanticTokensOptions struct {
+	Legend SemanticTokensLegend `json:"legend"`
+	Full   bool                 `json:"full"`
+}
+
+// SemanticTokens holds five integers per token: the line, relative to the
+// previous token, the start character, relative to the previous token if on
+// the same line, the length, the index of the type in the legend and the
+// bits of the modifiers.
+type SemanticTokens struct {
+	Data []int `json:"data"`
+}
+
+type DidOpenTextDocumentParams struct {
+	TextDocument TextDocumentItem `json:"textDocument"`
+}
+
+type TextDocumentContentChangeEvent struct {
+	Text string `json:"text"`
+}
+
+type DidChangeTextDocumentParams struct {
+	TextDocument   TextDocumentIdentifier            `json:"textDocument"`
+	ContentChanges []*TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type DiagnosticSeverity int
+
+const (
+	SeverityError       DiagnosticSeverity = 1
+	SeverityWarning     DiagnosticSeverity = 2
+	SeverityInformation DiagnosticSeverity = 3
+	SeverityHint        DiagnosticSeverity = 4
+)
+
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Code     string             `json:"code,omitempty"`
+	Source   string             `json:"source"`
+	Message  string             `json:"message"`
+}
+
+type PublishDiagnosticsParams struct {
+	URI         string        `json:"uri"`
+	Diagnostics []*Diagnostic `json:"diagnostics"`
+}
+
+type SymbolKind int
+
+const (
+	SymbolKindClass  SymbolKind = 5
+	SymbolKindMethod SymbolKind = 6
+	SymbolKindArray  SymbolKind = 18
+)
+
+type DocumentSymbol struct {
+	Name           string            `json:"name"`
+	Detail         string            `json:"detail,omitempty"`
+	Kind           SymbolKind        `json:"kind"`
+	Range          Range             `json:"range"`
+	SelectionRange Range             `json:"selectionRange"`
+	Children       []*DocumentSymbol `json:"children,omitempty"`
+}
+
+type FoldingRange struct {
+	StartLine int    `json:"startLine"`
+	EndLine   int    `json:"endLine"`
+	Kind      string `json:"kind,omitempty"`
+}
+
+type CompletionItemKind int
+
+const (
+	CompletionItemKindText    CompletionItemKind = 1
+	CompletionItemKindKeyword CompletionItemKind = 14
+	CompletionItemKindValue   CompletionItemKind = 12
+)
+
+type CompletionItem struct {
+	Label    string             `json:"label"`
+	Kind     CompletionItemKind `json:"kind,omitempty"`
+	Detail   string             `json:"detail,omitempty"`
+	TextEdit *TextEdit          `json:"textEdit,omitempty"`
+}
+
+type CompletionList struct {
+	IsIncomplete bool              `json:"isIncomplete"`
+	Items        []*CompletionItem `json:"items"`
+}
+
+// ----------------------------------------
+
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"` // nil for notifications
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *ResponseError   `json:"error,omitempty"`
+}
+
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+const (
+	CodeParseError           = -32700
+	CodeInvalidParams        = -32602
+	CodeMethodNotFound       = -32601
+	CodeInternalError        = -32603
+	CodeServerNotInitialized = -32002
+)
+
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ResponseError) Error() string {
+	return e.Message
+}
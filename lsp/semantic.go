@@ -0,0 +1,64 @@
+package lsp
+
+import (
+	"encoding/json"
+
+	"github.com/muhqu/go-gherkin"
+)
+
+// The token types of the legend are standard ones, so that editor themes
+// color them without further configuration.
+var semanticTokensLegend = SemanticTokensLegend{
+	TokenTypes:     []string{"keyword", "class", "comment", "decorator", "function", "string", "variable", "operator"},
+	TokenModifiers: []string{"documentation"},
+}
+
+type semanticTokenType struct {
+	index     int // into the token types of the legend
+	modifiers int
+}
+
+var semanticTokenTypes = map[gherkin.TokenType]semanticTokenType{
+	gherkin.KeywordToken:            {0, 0},
+	gherkin.TitleToken:              {1, 0},
+	gherkin.DescriptionToken:        {2, 1},
+	gherkin.TagToken:                {3, 0},
+	gherkin.StepKeywordToken:        {0, 0},
+	gherkin.StepTextToken:           {4, 0},
+	gherkin.ParameterToken:          {5, 0},
+	gherkin.PlaceholderToken:        {6, 0},
+	gherkin.TablePipeToken:          {7, 0},
+	gherkin.TableCellToken:          {5, 0},
+	gherkin.DocStringDelimiterToken: {7, 0},
+	gherkin.DocStringToken:          {5, 0},
+	gherkin.CommentToken:            {2, 0},
+}
+
+// semanticTokens returns the tokens of the whole document. Markdown and
+// documents that do not parse have none.
+func (s *server) semanticTokens(params json.RawMessage) (interface{}, error) {
+	result := &SemanticTokens{Data: []int{}}
+	doc, err := s.document(params)
+	if err != nil || doc == nil || doc.err != nil || gherkin.IsMarkdownFile(doc.uri) {
+		return result, err
+	}
+	tokens, err := gherkin.Tokenize(doc.text)
+	if err != nil {
+		return result, nil
+	}
+	var last Position
+	for _, t := range tokens {
+		typ, ok := semanticTokenTypes[t.Type]
+		if !ok {
+			continue
+		}
+		pos := doc.position(t.Pos)
+		character := pos.Character
+		if pos.Line == last.Line {
+			character -= last.Character
+		}
+		result.Data = append(result.Data, pos.Line-last.Line, character, utf16Len(t.Text(doc.text)), typ.index, typ.modifiers)
+		last = pos
+	}
+	return result, nil
+}
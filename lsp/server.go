@@ -0,0 +1,237 @@
+// Sub-Package gherkin/lsp implements a Language Server Protocol server for
+// feature files, speaking JSON-RPC over stdio.
+//
+// It publishes diagnostics from parse errors and lint, formats documents with
+// the pretty formater and provides a document outline, folding ranges,
+// semantic tokens for highlighting and completion of keywords, tags and the
+// step texts found in the workspace.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/lint"
+)
+
+type Server interface {
+	// Serve handles the messages read from in, writing responses and
+	// notifications to out, until the client sends exit. It returns nil if
+	// the client asked to shut down before.
+	Serve(in io.Reader, out io.Writer) error
+}
+
+// NewServer returns a server reporting the diagnostics of linter, or of the
+// built-in lint rules if linter is nil.
+func NewServer(linter lint.Linter) Server {
+	if linter == nil {
+		linter = lint.NewLinter()
+	}
+	return &server{
+		linter:    linter,
+		documents: make(map[string]*document),
+		workspace: newWorkspace(),
+	}
+}
+
+type server struct {
+	linter    lint.Linter
+	out       io.Writer
+	documents map[string]*document // by URI
+	workspace *workspace
+
+	initialized bool
+	shutdown    bool
+}
+
+type handler func(s *server, params json.RawMessage) (interface{}, error)
+
+var handlers map[string]handler
+
+func init() {
+	handlers = map[string]handler{
+		"initialize":                       (*server).initialize,
+		"initialized":                      (*server).ignore,
+		"shutdown":                         (*server).shutdownRequest,
+		"textDocument/didOpen":             (*server).didOpen,
+		"textDocument/didChange":           (*server).didChange,
+		"textDocument/didClose":            (*server).didClose,
+		"textDocument/didSave":             (*server).ignore,
+		"textDocument/formatting":          (*server).formatting,
+		"textDocument/documentSymbol":      (*server).documentSymbol,
+		"textDocument/foldingRange":        (*server).foldingRange,
+		"textDocument/completion":          (*server).completion,
+		"textDocument/semanticTokens/full": (*server).semanticTokens,
+	}
+}
+
+var errExitWithoutShutdown = errors.New("lsp: exit without shutdown")
+
+func (s *server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	r := bufio.NewReader(in)
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			if err == io.EOF && s.shutdown {
+				return nil
+			}
+			return err
+		}
+		req := &request{}
+		if err := json.Unmarshal(body, req); err != nil {
+			if err := s.reply(nil, nil, &ResponseError{CodeParseError, err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return errExitWithoutShutdown
+			}
+			return nil
+		}
+		result, err := s.handle(req)
+		if req.ID == nil {
+			continue // notifications get no response, not even errors
+		}
+		if err := s.reply(req.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *server) handle(req *request) (interface{}, error) {
+	h, ok := handlers[req.Method]
+	if !ok {
+		return nil, &ResponseError{CodeMethodNotFound, "method not found: " + req.Method}
+	}
+	if !s.initialized && req.Method != "initialize" {
+		return nil, &ResponseError{CodeServerNotInitialized, "server not initialized"}
+	}
+	return h(s, req.Params)
+}
+
+func (s *server) reply(id *json.RawMessage, result interface{}, err error) error {
+	resp := &response{JSONRPC: "2.0", ID: id}
+	if err != nil {
+		respErr, ok := err.(*ResponseError)
+		if !ok {
+			respErr = &ResponseError{CodeInternalError, err.Error()}
+		}
+		resp.Error = respErr
+	} else {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		resp.Result = data
+	}
+	return writeMessage(s.out, resp)
+}
+
+func (s *server) notify(method string, params interface{}) error {
+	return writeMessage(s.out, &notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func unmarshalParams(params json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &ResponseError{CodeInvalidParams, err.Error()}
+	}
+	return nil
+}
+
+// ----------------------------------------
+
+func (s *server) ignore(params json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
+func (s *server) initialize(params json.RawMessage) (interface{}, error) {
+	p := &InitializeParams{}
+	if err := unmarshalParams(params, p); err != nil {
+		return nil, err
+	}
+	if len(p.WorkspaceFolders) > 0 {
+		for _, folder := range p.WorkspaceFolders {
+			s.workspace.scan(uriToPath(folder.URI))
+		}
+	} else if p.RootURI != "" {
+		s.workspace.scan(uriToPath(p.RootURI))
+	}
+	s.initialized = true
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync:           TextDocumentSyncFull,
+			DocumentFormattingProvider: true,
+			DocumentSymbolProvider:     true,
+			FoldingRangeProvider:       true,
+			CompletionProvider:         &CompletionOptions{TriggerCharacters: []string{"@"}},
+			SemanticTokensProvider:     &SemanticTokensOptions{Legend: semanticTokensLegend, Full: true},
+		},
+		ServerInfo: &ServerInfo{Name: "gherkin", Version: gherkin.VERSION},
+	}, nil
+}
+
+func (s *server) shutdownRequest(params json.RawMessage) (interface{}, error) {
+	s.shutdown = true
+	return nil, nil
+}
+
+func (s *server) didOpen(params json.RawMessage) (interface{}, error) {
+	p := &DidOpenTextDocumentParams{}
+	if err := unmarshalParams(params, p); err != nil {
+		return nil, err
+	}
+	return nil, s.update(p.TextDocument.URI, p.TextDocument.Text)
+}
+
+func (s *server) didChange(params json.RawMessage) (interface{}, error) {
+	p := &DidChangeTextDocumentParams{}
+	if err := unmarshalParams(params, p); err != nil {
+		return nil, err
+	}
+	if n := len(p.ContentChanges); n > 0 {
+		return nil, s.update(p.TextDocument.URI, p.ContentChanges[n-1].Text)
+	}
+	return nil, nil
+}
+
+func (s *server) didClose(params json.RawMessage) (interface{}, error) {
+	p := &DidCloseTextDocumentParams{}
+	if err := unmarshalParams(params, p); err != nil {
+		return nil, err
+	}
+	delete(s.documents, p.TextDocument.URI)
+	return nil, s.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         p.TextDocument.URI,
+		Diagnostics: []*Diagnostic{},
+	})
+}
+
+// update replaces the document at uri and publishes its diagnostics.
+func (s *server) update(uri, text string) error {
+	doc := newDocument(uri, text)
+	s.documents[uri] = doc
+	if doc.feature != nil {
+		s.workspace.update(uriToPath(uri), doc.feature)
+	}
+	return s.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: s.diagnostics(doc),
+	})
+}
+
+// document returns the open document of a request, nil if there is none.
+func (s *server) document(params json.RawMessage) (*document, error) {
+	p := &struct {
+		TextDocument TextDocumentIdentifier `json:"textDocument"`
+	}{}
+	if err := unmarshalParams(params, p); err != nil {
+		return nil, err
+	}
+	return s.documents[p.TextDocument.URI], nil
+}
@@ -0,0 +1,309 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/muhqu/go-gherkin/lsp"
+	"github.com/stretchr/testify/assert"
+)
+
+// session collects the messages sent to the server.
+type session struct {
+	in bytes.Buffer
+	id int
+}
+
+func (s *session) send(method string, params interface{}) int {
+	s.id++
+	s.write(map[string]interface{}{"jsonrpc": "2.0", "id": s.id, "method": method, "params": params})
+	return s.id
+}
+
+func (s *session) notify(method string, params interface{}) {
+	s.write(map[string]interface{}{"jsonrpc": "2.0", "method": method, "params": params})
+}
+
+func (s *session) write(v interface{}) {
+	body, _ := json.Marshal(v)
+	fmt.Fprintf(&s.in, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+type message struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *lsp.ResponseError
+}
+
+// run serves the session and returns the messages written by the server.
+func (s *session) run(t *testing.T) []*message {
+	var out bytes.Buffer
+	assert.NoError(t, lsp.NewServer(nil).Serve(&s.in, &out))
+
+	var messages []*message
+	r := bufio.NewReader(&out)
+	for {
+		header, err := r.ReadString('\n')
+		if err == io.EOF {
+			return messages
+		}
+		r.ReadString('\n')
+		length, _ := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "Content-Length:")))
+		body := make([]byte, length)
+		io.ReadFull(r, body)
+		m := &message{}
+		assert.NoError(t, json.Unmarshal(body, m))
+		messages = append(messages, m)
+	}
+}
+
+func result(t *testing.T, messages []*message, id int, v interface{}) {
+	for _, m := range messages {
+		if m.ID != nil && *m.ID == id {
+			if assert.Nil(t, m.Error) {
+				assert.NoError(t, json.Unmarshal(m.Result, v))
+			}
+			return
+		}
+	}
+	t.Errorf("no response to request %d", id)
+}
+
+func diagnostics(t *testing.T, messages []*message) [][]*lsp.Diagnostic {
+	var all [][]*lsp.Diagnostic
+	for _, m := range messages {
+		if m.Method == "textDocument/publishDiagnostics" {
+			p := &lsp.PublishDiagnosticsParams{}
+			assert.NoError(t, json.Unmarshal(m.Params, p))
+			all = append(all, p.Diagnostics)
+		}
+	}
+	return all
+}
+
+const uri = "file:///tmp/calc.feature"
+
+const calcFeature = `@math
+Feature: Calculator
+
+  Scenario: Adding
+    Given a calculator
+    When I add:
+      | a | b |
+      | 1 | 2 |
+    Then the result is 3
+
+  Scenario Outline: Subtracting
+    Given a calculator
+    When I subtract <b> from <a>
+    Then the result is <c>
+
+    Examples:
+      | a | b | c |
+      | 5 | 3 | 2 |
+`
+
+func textDocument() map[string]string {
+	return map[string]string{"uri": uri}
+}
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lsp")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "other.feature"), []byte("Feature: Other\n\n  @slow\n  Scenario: S\n    Given a printer\n"), 0644)
+
+	s := &session{}
+	initialize := s.send("initialize", map[string]interface{}{"rootUri": "file://" + dir})
+	s.notify("initialized", struct{}{})
+	s.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "gherkin", "version": 1, "text": calcFeature},
+	})
+	symbols := s.send("textDocument/documentSymbol", map[string]interface{}{"textDocument": textDocument()})
+	folding := s.send("textDocument/foldingRange", map[string]interface{}{"textDocument": textDocument()})
+	formatting := s.send("textDocument/formatting", map[string]interface{}{"textDocument": textDocument()})
+	semantic := s.send("textDocument/semanticTokens/full", map[string]interface{}{"textDocument": textDocument()})
+	s.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []map[string]string{{"text": "Feature: Broken\n  Scenario: S\n    Given x\n    | a | b\n"}},
+	})
+	unknown := s.send("textDocument/hover", map[string]interface{}{"textDocument": textDocument()})
+	shutdown := s.send("shutdown", nil)
+	s.notify("exit", nil)
+	messages := s.run(t)
+
+	init := &lsp.InitializeResult{}
+	result(t, messages, initialize, init)
+	assert.Equal(t, lsp.TextDocumentSyncFull, init.Capabilities.TextDocumentSync)
+	assert.True(t, init.Capabilities.DocumentFormattingProvider)
+	assert.Equal(t, "keyword", init.Capabilities.SemanticTokensProvider.Legend.TokenTypes[0])
+
+	var outline []*lsp.DocumentSymbol
+	result(t, messages, symbols, &outline)
+	if assert.Len(t, outline, 1) {
+		feature := outline[0]
+		assert.Equal(t, "Feature: Calculator", feature.Name)
+		assert.Equal(t, lsp.Range{Start: lsp.Position{1, 0}, End: lsp.Position{18, 0}}, feature.Range)
+		if assert.Len(t, feature.Children, 2) {
+			assert.Equal(t, "Scenario: Adding", feature.Children[0].Name)
+			assert.Equal(t, lsp.Range{Start: lsp.Position{3, 0}, End: lsp.Position{8, 24}}, feature.Children[0].Range)
+			assert.Equal(t, lsp.Range{Start: lsp.Position{3, 2}, End: lsp.Position{3, 18}}, feature.Children[0].SelectionRange)
+			assert.Equal(t, "Scenario Outline: Subtracting", feature.Children[1].Name)
+			if assert.Len(t, feature.Children[1].Children, 1) {
+				assert.Equal(t, "Examples", feature.Children[1].Children[0].Name)
+				assert.Equal(t, lsp.Range{Start: lsp.Position{15, 0}, End: lsp.Position{17, 19}}, feature.Children[1].Children[0].Range)
+			}
+		}
+	}
+
+	var ranges []*lsp.FoldingRange
+	result(t, messages, folding, &ranges)
+	assert.Equal(t, []*lsp.FoldingRange{{StartLine: 3, EndLine: 8}, {StartLine: 6, EndLine: 7}, {StartLine: 10, EndLine: 17}, {StartLine: 15, EndLine: 17}}, ranges)
+
+	var edits []*lsp.TextEdit
+	result(t, messages, formatting, &edits)
+	assert.Len(t, edits, 0) // already formatted
+
+	tokens := &lsp.SemanticTokens{}
+	result(t, messages, semantic, tokens)
+	if assert.True(t, len(tokens.Data) > 35) {
+		assert.Equal(t, []int{
+			0, 0, 5, 3, 0, // @math
+			1, 0, 8, 0, 0, // Feature:
+			0, 9, 10, 1, 0, // Calculator
+			2, 2, 9, 0, 0, // Scenario:
+			0, 10, 6, 1, 0, // Adding
+			1, 4, 5, 0, 0, // Given
+			0, 6, 12, 4, 0, // a calculator
+		}, tokens.Data[:35])
+	}
+
+	all := diagnostics(t, messages)
+	if assert.Len(t, all, 2) {
+		assert.Equal(t, []*lsp.Diagnostic{}, all[0])
+		assert.Equal(t, []*lsp.Diagnostic{{
+			Range:    lsp.Range{Start: lsp.Position{3, 4}, End: lsp.Position{3, 11}},
+			Severity: lsp.SeverityError,
+			Source:   "gherkin",
+			Message:  `unexpected "| a | b"`,
+		}}, all[1])
+	}
+
+	for _, m := range messages {
+		if m.ID != nil && *m.ID == unknown {
+			assert.Equal(t, lsp.CodeMethodNotFound, m.Error.Code)
+		}
+	}
+	var null interface{}
+	result(t, messages, shutdown, &null)
+	assert.Nil(t, null)
+}
+
+func TestServerCompletion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lsp")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "other.feature"), []byte("Feature: Other\n\n  @slow\n  Scenario: S\n    Given a printer\n"), 0644)
+
+	text := "@math\nFeature: Calculator\n\n  @sm\n  Scenario: Adding\n    Given a calculator\n    And a pr\n    \n"
+	s := &session{}
+	s.send("initialize", map[string]interface{}{"workspaceFolders": []map[string]string{{"uri": "file://" + dir, "name": "w"}}})
+	s.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "gherkin", "version": 1, "text": text},
+	})
+	complete := func(line, character int) int {
+		return s.send("textDocument/completion", map[string]interface{}{
+			"textDocument": textDocument(),
+			"position":     map[string]int{"line": line, "character": character},
+		})
+	}
+	tags, steps, keywords := complete(3, 5), complete(6, 12), complete(7, 4)
+	s.send("shutdown", nil)
+	s.notify("exit", nil)
+	messages := s.run(t)
+
+	list := &lsp.CompletionList{}
+	result(t, messages, tags, list)
+	var labels []string
+	for _, item := range list.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.Equal(t, []string{"@math", "@slow", "@sm"}, labels)
+	assert.Equal(t, &lsp.TextEdit{Range: lsp.Range{Start: lsp.Position{3, 2}, End: lsp.Position{3, 5}}, NewText: "@math"}, list.Items[0].TextEdit)
+
+	list = &lsp.CompletionList{}
+	result(t, messages, steps, list)
+	labels = nil
+	for _, item := range list.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.Equal(t, []string{"a calculator", "a pr", "a printer"}, labels)
+	assert.Equal(t, lsp.Range{Start: lsp.Position{6, 8}, End: lsp.Position{6, 12}}, list.Items[0].TextEdit.Range)
+
+	list = &lsp.CompletionList{}
+	result(t, messages, keywords, list)
+	if assert.NotEmpty(t, list.Items) {
+		assert.Equal(t, "Feature:", list.Items[0].Label)
+		assert.Equal(t, &lsp.TextEdit{Range: lsp.Range{Start: lsp.Position{7, 4}, End: lsp.Position{7, 4}}, NewText: "Feature:"}, list.Items[0].TextEdit)
+		assert.Equal(t, "Given", list.Items[5].Label)
+		assert.Equal(t, "Given ", list.Items[5].TextEdit.NewText)
+	}
+}
+
+func TestServerEmptyDocument(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lsp")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "empty.feature"), nil, 0644)
+
+	s := &session{}
+	s.send("initialize", map[string]interface{}{"rootUri": "file://" + dir})
+	s.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "gherkin", "version": 1, "text": ""},
+	})
+	symbols := s.send("textDocument/documentSymbol", map[string]interface{}{"textDocument": textDocument()})
+	folding := s.send("textDocument/foldingRange", map[string]interface{}{"textDocument": textDocument()})
+	formatting := s.send("textDocument/formatting", map[string]interface{}{"textDocument": textDocument()})
+	s.send("shutdown", nil)
+	s.notify("exit", nil)
+	messages := s.run(t)
+
+	if all := diagnostics(t, messages); assert.Len(t, all, 1) && assert.Len(t, all[0], 1) {
+		assert.Equal(t, lsp.SeverityWarning, all[0][0].Severity)
+		assert.Equal(t, "file has no feature", all[0][0].Message)
+	}
+	var list []interface{}
+	result(t, messages, symbols, &list)
+	assert.Empty(t, list)
+	result(t, messages, folding, &list)
+	assert.Empty(t, list)
+	result(t, messages, formatting, &list)
+	assert.Empty(t, list)
+}
+
+func TestServerErrors(t *testing.T) {
+	s := &session{}
+	id := s.send("textDocument/formatting", map[string]interface{}{"textDocument": textDocument()})
+	s.notify("exit", nil)
+	var out bytes.Buffer
+	err := lsp.NewServer(nil).Serve(&s.in, &out)
+	assert.EqualError(t, err, "lsp: exit without shutdown")
+	assert.Contains(t, out.String(), fmt.Sprintf(`"id":%d,"error":{"code":%d`, id, lsp.CodeServerNotInitialized))
+}
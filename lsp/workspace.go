@@ -0,0 +1,98 @@
+package lsp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+// workspace indexes the step texts and tags of the feature files of the
+// workspace, for completion. Open documents replace the files on disk.
+type workspace struct {
+	files map[string]*fileIndex // by path
+}
+
+type fileIndex struct {
+	steps []string
+	tags  []string
+}
+
+func newWorkspace() *workspace {
+	return &workspace{files: make(map[string]*fileIndex)}
+}
+
+// scan indexes the feature files below root. Files that do not parse are
+// skipped.
+func (w *workspace) scan(root string) {
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".feature") && !gherkin.IsMarkdownFile(path) {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if feature, err := gherkin.ParseFeatureFile(path, string(data)); err == nil {
+			w.update(path, feature)
+		}
+		return nil
+	})
+}
+
+func (w *workspace) update(path string, feature nodes.FeatureNode) {
+	index := &fileIndex{tags: feature.Tags()}
+	var scenarios []nodes.ScenarioNode
+	if feature.Background() != nil {
+		scenarios = append(scenarios, feature.Background())
+	}
+	for _, scenario := range append(scenarios, feature.Scenarios()...) {
+		index.tags = append(index.tags, scenario.Tags()...)
+		for _, step := range scenario.Steps() {
+			index.steps = append(index.steps, step.Text())
+		}
+	}
+	w.files[path] = index
+}
+
+// steps returns the distinct step texts, sorted.
+func (w *workspace) steps() []string {
+	var all []string
+	for _, index := range w.files {
+		all = append(all, index.steps...)
+	}
+	return unique(all)
+}
+
+// tags returns the distinct tags without '@', sorted.
+func (w *workspace) tags() []string {
+	var all []string
+	for _, index := range w.files {
+		all = append(all, index.tags...)
+	}
+	return unique(all)
+}
+
+func unique(s []string) []string {
+	sort.Strings(s)
+	var u []string
+	for i, str := range s {
+		if i == 0 || str != s[i-1] {
+			u = append(u, str)
+		}
+	}
+	return u
+}
@@ -0,0 +1,376 @@
+package gherkin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// NewMarkdownGherkinParser returns a parser for Markdown with Gherkin (MDG),
+// as found in .feature.md files. It emits the same events as the parser
+// returned by NewGherkinParser.
+//
+//	# Feature: Calculator           <- headings starting with a keyword
+//	Some description.
+//
+//	`@wip`                          <- tags, with or without backticks
+//	## Scenario: Adding
+//
+//	* Given a calculator            <- list items starting with a step type
+//	* When I add:
+//	  | a | b |                     <- tables, the delimiter row is skipped
+//	  |---|---|
+//	  | 1 | 2 |
+//
+// Fenced code blocks following a step become its PyString. Other Markdown is
+// taken as the description when it directly follows a heading and is skipped
+// otherwise. Blank lines are not reported, as Markdown needs them between
+// blocks anyway.
+func NewMarkdownGherkinParser(content string) GherkinParser {
+	if !strings.HasSuffix(content, "\n") {
+		content = content + "\n"
+	}
+	return &markdownParser{content: content}
+}
+
+type markdownParser struct {
+	gherkinPegBase
+
+	content string
+	actions []func()
+}
+
+func (mp *markdownParser) WithLogFn(logFn LogFn) {
+	mp.logFn = logFn
+}
+
+func (mp *markdownParser) WithEventProcessor(ep EventProcessor) {
+	mp.eventProcessors = append(mp.eventProcessors, ep)
+}
+
+func (mp *markdownParser) Init() {
+	mp.lines = newLineIndex([]rune(mp.content))
+	mp.actions = nil
+	mp.err = nil
+}
+
+// Parse checks the structure of the content and records the events, which
+// are emitted by Execute.
+func (mp *markdownParser) Parse() error {
+	s := &mdgScanner{mp: mp}
+	offset := 0
+	for _, text := range strings.SplitAfter(mp.content, "\n") {
+		if text != "" {
+			s.lines = append(s.lines, mdgLine{text: strings.TrimRight(text, "\r\n"), offset: offset})
+		}
+		offset += utf8.RuneCountInString(text)
+	}
+	mp.actions = nil
+	return s.scan()
+}
+
+func (mp *markdownParser) Execute() error {
+	for _, action := range mp.actions {
+		if mp.err != nil {
+			break
+		}
+		action()
+	}
+	return mp.err
+}
+
+// ----------------------------------------
+
+var (
+	mdgHeading   = regexp.MustCompile(`^ {0,3}#{1,6}[ \t]+(Feature|Background|Scenario Outline|Scenario|Examples):`)
+	mdgStep      = regexp.MustCompile(`^[ \t]*[*+-][ \t]+(Given|When|Then|And|Or|But)[ \t]`)
+	mdgTag       = regexp.MustCompile("^`?@([^\\s`\"#]+)`?$")
+	mdgTableRow  = regexp.MustCompile(`^[ \t]*\|`)
+	mdgFence     = regexp.MustCompile("^([ \t]*)(```+|~~~+)")
+	mdgDelimiter = regexp.MustCompile(`^:?-+:?$`)
+)
+
+type mdgLine struct {
+	text   string
+	offset int // rune offset of the line
+}
+
+// runeOffset returns the rune offset of the byte index i of the line.
+func (l mdgLine) runeOffset(i int) int {
+	return l.offset + utf8.RuneCountInString(l.text[:i])
+}
+
+func (l mdgLine) isBlank() bool {
+	return trimWS(l.text) == ""
+}
+
+// isProse reports whether the line is neither a keyword, nor tags, nor part of
+// a table or fenced code block.
+func (l mdgLine) isProse() bool {
+	return !mdgHeading.MatchString(l.text) && !mdgStep.MatchString(l.text) &&
+		!mdgTableRow.MatchString(l.text) && !mdgFence.MatchString(l.text) && l.tags() == nil
+}
+
+// tags returns the tags if the line holds nothing but tags.
+func (l mdgLine) tags() (tags []string) {
+	for _, field := range strings.Fields(l.text) {
+		m := mdgTag.FindStringSubmatch(field)
+		if m == nil {
+			return nil
+		}
+		tags = append(tags, m[1])
+	}
+	return tags
+}
+
+type mdgScanner struct {
+	mp    *markdownParser
+	lines []mdgLine
+	i     int
+
+	feature     bool
+	scenario    string // keyword of the open scenario
+	examples    bool
+	step        bool
+	table       bool
+	hasArgument bool // the open step or examples already has a table or PyString
+
+	tags       []string
+	tagOffsets []int
+}
+
+func (s *mdgScanner) add(action func()) {
+	s.mp.actions = append(s.mp.actions, action)
+}
+
+func (s *mdgScanner) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	return &ParseError{Pos: s.mp.lines.position(s.lines[s.i].offset), Msg: msg}
+}
+
+func (s *mdgScanner) scan() error {
+	mp := s.mp
+	for s.i = 0; s.i < len(s.lines); s.i++ {
+		line := s.lines[s.i]
+
+		if mdgTableRow.MatchString(line.text) {
+			s.tableRow(line)
+			continue
+		}
+		s.closeTable()
+
+		if m := mdgFence.FindStringSubmatch(line.text); m != nil {
+			if err := s.fence(line, m[1], m[2]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if m := mdgHeading.FindStringSubmatchIndex(line.text); m != nil {
+			if err := s.heading(line, line.text[m[2]:m[3]], m[1]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if m := mdgStep.FindStringSubmatchIndex(line.text); m != nil {
+			if s.scenario == "" || s.examples {
+				return s.errorf("step outside of a scenario")
+			}
+			s.closeStep()
+			stepType, text, offset := line.text[m[2]:m[3]], trimWS(line.text[m[3]:]), line.runeOffset(m[2])
+			s.add(func() { mp.beginStep(stepType, text, offset) })
+			s.step, s.hasArgument = true, false
+			s.tags, s.tagOffsets = nil, nil
+			continue
+		}
+
+		if tags := line.tags(); tags != nil {
+			s.tags = append(s.tags, tags...)
+			for i := 0; i < len(line.text); i++ {
+				if line.text[i] == '@' {
+					s.tagOffsets = append(s.tagOffsets, line.runeOffset(i+1))
+				}
+			}
+		}
+	}
+	s.closeFeature()
+	return nil
+}
+
+func (s *mdgScanner) heading(line mdgLine, keyword string, end int) error {
+	mp := s.mp
+	title, titleOffset := trimWS(line.text[end:]), line.runeOffset(end)
+	tags, tagOffsets := s.tags, s.tagOffsets
+	s.tags, s.tagOffsets = nil, nil
+
+	switch keyword {
+	case "Feature":
+		if s.feature {
+			return s.errorf("more than one Feature")
+		}
+		description := s.description()
+		s.add(func() { mp.beginFeature(title, description, tags, tagOffsets, titleOffset) })
+		s.feature = true
+
+	case "Examples":
+		if s.scenario != "Scenario Outline" {
+			return s.errorf("Examples outside of a Scenario Outline")
+		}
+		s.closeExamples()
+		s.add(func() { mp.beginOutlineExamples(title, titleOffset) })
+		s.examples, s.hasArgument = true, false
+
+	default:
+		if !s.feature {
+			return s.errorf("%s outside of a Feature", keyword)
+		}
+		s.closeScenario()
+		description := s.description()
+		switch keyword {
+		case "Background":
+			s.add(func() { mp.beginBackground(title, description, tags, tagOffsets, titleOffset) })
+		case "Scenario":
+			s.add(func() { mp.beginScenario(title, description, tags, tagOffsets, titleOffset) })
+		case "Scenario Outline":
+			s.add(func() { mp.beginOutline(title, description, tags, tagOffsets, titleOffset) })
+		}
+		s.scenario = keyword
+	}
+	return nil
+}
+
+// description consumes the prose following a heading.
+func (s *mdgScanner) description() string {
+	var lines []string
+	for s.i+1 < len(s.lines) && (s.lines[s.i+1].isBlank() || s.lines[s.i+1].isProse()) {
+		s.i++
+		lines = append(lines, s.lines[s.i].text)
+	}
+	return trimWSML(strings.Join(lines, "\n"))
+}
+
+// tableRow adds line to the table of the open step or examples, or skips it
+// if there is none.
+func (s *mdgScanner) tableRow(line mdgLine) {
+	mp := s.mp
+	if !s.table {
+		if !(s.step || s.examples) || s.hasArgument {
+			return
+		}
+		s.add(mp.beginTable)
+		s.table, s.hasArgument = true, true
+	}
+
+	type cell struct {
+		content string
+		offset  int
+	}
+	var cells []cell
+	delimiter := true
+	text := line.text
+	start := strings.Index(text, "|") + 1
+	for i := start; i <= len(text); i++ {
+		if i < len(text) && text[i] == '\\' {
+			i++
+			continue
+		}
+		if i < len(text) && text[i] != '|' {
+			continue
+		}
+		content := text[start:i]
+		if i == len(text) && trimWS(content) == "" {
+			break // no content after the last pipe
+		}
+		content = strings.Replace(content, "\\|", "|", -1)
+		cells = append(cells, cell{content, line.runeOffset(start)})
+		delimiter = delimiter && mdgDelimiter.MatchString(trimWS(content))
+		start = i + 1
+	}
+	if delimiter {
+		return
+	}
+
+	s.add(mp.beginTableRow)
+	for _, c := range cells {
+		c := c
+		s.add(func() {
+			mp.beginTableCell()
+			mp.endTableCell(c.content, c.offset)
+		})
+	}
+	s.add(mp.endTableRow)
+}
+
+// fence consumes a fenced code block, which becomes the PyString of the open
+// step.
+func (s *mdgScanner) fence(line mdgLine, indent, fence string) error {
+	mp := s.mp
+	argument := s.step && !s.hasArgument
+	if argument {
+		offset := line.offset
+		mediaType := trimWS(trimLeadingWS(line.text)[len(fence):])
+		s.add(func() { mp.beginPyString(indent, offset, mediaType) })
+		s.hasArgument = true
+	}
+	start := s.i
+	for s.i++; s.i < len(s.lines); s.i++ {
+		l := s.lines[s.i]
+		if rest := trimLeadingWS(l.text); strings.HasPrefix(rest, fence) && trimWS(strings.TrimLeft(rest, fence[:1])) == "" {
+			if argument {
+				s.add(mp.endPyString)
+			}
+			return nil
+		}
+		if argument {
+			s.add(func() { mp.bufferPyString(l.text, l.offset) })
+		}
+	}
+	s.i = start
+	return s.errorf("unterminated code block")
+}
+
+func (s *mdgScanner) closeTable() {
+	if s.table {
+		s.add(s.mp.endTable)
+		s.table = false
+	}
+}
+
+func (s *mdgScanner) closeStep() {
+	s.closeTable()
+	if s.step {
+		s.add(s.mp.endStep)
+		s.step = false
+	}
+}
+
+func (s *mdgScanner) closeExamples() {
+	s.closeStep()
+	if s.examples {
+		s.add(s.mp.endOutlineExamples)
+		s.examples = false
+	}
+}
+
+func (s *mdgScanner) closeScenario() {
+	s.closeExamples()
+	switch s.scenario {
+	case "Background":
+		s.add(s.mp.endBackground)
+	case "Scenario":
+		s.add(s.mp.endScenario)
+	case "Scenario Outline":
+		s.add(s.mp.endOutline)
+	}
+	s.scenario = ""
+}
+
+func (s *mdgScanner) closeFeature() {
+	s.closeScenario()
+	if s.feature {
+		s.add(s.mp.endFeature)
+		s.feature = false
+	}
+}
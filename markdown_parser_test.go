@@ -0,0 +1,191 @@
+package gherkin_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/stretchr/testify/assert"
+)
+
+const calculatorMarkdown = "# Feature: Calculator\n" +
+	"\n" +
+	"Simple math,\n" +
+	"*without* a pocket calculator.\n" +
+	"\n" +
+	"## Background:\n" +
+	"\n" +
+	"* Given a calculator\n" +
+	"\n" +
+	"`@wip` @math\n" +
+	"## Scenario Outline: Adding\n" +
+	"\n" +
+	"- When I add <a> and <b>\n" +
+	"- Then the result is <sum>\n" +
+	"\n" +
+	"### Examples: Small numbers\n" +
+	"\n" +
+	"| a | b | sum |\n" +
+	"|---|:-:|----:|\n" +
+	"| 1 | 2 | 3   |\n" +
+	"\n" +
+	"## Scenario: Typing\n" +
+	"\n" +
+	"* When I type:\n" +
+	"  ```\n" +
+	"  2 + 2\n" +
+	"\n" +
+	"  = 4\n" +
+	"  ```\n" +
+	"* And I press the keys:\n" +
+	"  | key | note     |\n" +
+	"  | --- | -------- |\n" +
+	"  | \\|  | the pipe |\n" +
+	"  Not a step, as it is no list item.\n" +
+	"* Then the result is 4\n"
+
+const calculatorGherkin = `Feature: Calculator
+  Simple math,
+  *without* a pocket calculator.
+
+  Background:
+    Given a calculator
+
+  @wip @math
+  Scenario Outline: Adding
+    When I add <a> and <b>
+    Then the result is <sum>
+
+    Examples: Small numbers
+      | a | b | sum |
+      | 1 | 2 | 3   |
+
+  Scenario: Typing
+    When I type:
+      """
+      2 + 2
+
+      = 4
+      """
+    And I press the keys:
+      | key | note     |
+      | pipe | the pipe |
+    Then the result is 4
+`
+
+func eventStrings(gp gherkin.GherkinParser) ([]string, error) {
+	var out []string
+	gp.WithEventProcessor(gherkin.EventProcessorFn(func(e gherkin.GherkinEvent) {
+		switch e.(type) {
+		case *events.BlankLineEvent, *events.PyStringEvent, *events.PyStringLineEvent:
+		default:
+			out = append(out, fmt.Sprint(e))
+		}
+	}))
+	gp.Init()
+	if err := gp.Parse(); err != nil {
+		return nil, err
+	}
+	gp.Execute()
+	return out, nil
+}
+
+func TestMarkdownParserEmitsSameEvents(t *testing.T) {
+	expected, err := eventStrings(gherkin.NewGherkinParser(calculatorGherkin))
+	assert.NoError(t, err)
+	actual, err := eventStrings(gherkin.NewMarkdownGherkinParser(calculatorMarkdown))
+	assert.NoError(t, err)
+
+	for i := range expected {
+		if expected[i] == `TableCellEvent("pipe")` {
+			expected[i] = `TableCellEvent("|")`
+		}
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestMarkdownParserDOM(t *testing.T) {
+	feature, err := gherkin.ParseMarkdownGherkinFeature(calculatorMarkdown)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Calculator", feature.Title())
+	assert.Equal(t, events.Position{Offset: 2, Line: 1, Column: 3}, feature.Position())
+	assert.Equal(t, "6:4", feature.Background().Position().String())
+
+	outline := feature.Scenarios()[0].(nodes.OutlineNode)
+	assert.Equal(t, []string{"wip", "math"}, outline.Tags())
+	assert.Equal(t, "10:2", outline.TagPositions()[0].String())
+	assert.Equal(t, "10:8", outline.TagPositions()[1].String())
+	assert.Equal(t, "13:3", outline.Steps()[0].Position().String())
+	assert.Equal(t, "16:5", outline.Examples().Position().String())
+	assert.Equal(t, [][]string{{"a", "b", "sum"}, {"1", "2", "3"}}, outline.Examples().Table().Rows())
+	assert.Equal(t, "20:1", outline.Examples().Table().RowPositions()[1].String())
+
+	typing := feature.Scenarios()[1]
+	assert.Equal(t, []string{"2 + 2", "", "= 4"}, typing.Steps()[0].PyString().Lines())
+	assert.Equal(t, "25:3", typing.Steps()[0].PyString().Position().String())
+	assert.Equal(t, "33:5", typing.Steps()[1].Table().CellPositions()[1][0].String())
+}
+
+func TestMarkdownParserErrors(t *testing.T) {
+	for content, expected := range map[string]struct{ msg, pos string }{
+		"## Scenario: S\n":                                  {"line 1: Scenario outside of a Feature", "1:1"},
+		"# Feature: A\n# Feature: B\n":                      {"line 2: more than one Feature", "2:1"},
+		"# Feature: F\n## Scenario: S\n### Examples:\n":     {"line 3: Examples outside of a Scenario Outline", "3:1"},
+		"# Feature: F\n* Given a step\n":                    {"line 2: step outside of a scenario", "2:1"},
+		"# Feature: F\n## Scenario: S\n* Given a\n```\nx\n": {"line 4: unterminated code block", "4:1"},
+	} {
+		_, err := gherkin.ParseMarkdownGherkinFeature(content)
+		if assert.Error(t, err, content) {
+			assert.Equal(t, expected.msg, err.Error())
+			assert.Equal(t, expected.pos, err.(*gherkin.ParseError).Position().String())
+			assert.Nil(t, err.(*gherkin.ParseError).Unwrap())
+		}
+	}
+}
+
+func TestParsingPyStringWithBlankLine(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature("Feature: F\n  Scenario: S\n    When x\n      \"\"\"\n      a\n\n      b\n      \"\"\"\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "", "b"}, feature.Scenarios()[0].Steps()[0].PyString().Lines())
+}
+
+func TestParsingPyStringMediaType(t *testing.T) {
+	gherkinText := "Feature: F\n  Scenario: S\n    When x\n      \"\"\"json\n      {}\n      \"\"\"\n    Then y\n      \"\"\"\n      z\n      \"\"\"\n"
+	markdownText := "# Feature: F\n## Scenario: S\n* When x\n  ```json\n  {}\n  ```\n* Then y\n  ```\n  z\n  ```\n"
+	features := map[string]nodes.FeatureNode{}
+	var err error
+	features["peg"], err = gherkin.ParseGherkinFeature(gherkinText)
+	assert.NoError(t, err)
+	features["stream"], err = gherkin.ParseGherkinFeatureStream(strings.NewReader(gherkinText))
+	assert.NoError(t, err)
+	features["markdown"], err = gherkin.ParseMarkdownGherkinFeature(markdownText)
+	assert.NoError(t, err)
+	for name, feature := range features {
+		steps := feature.Scenarios()[0].Steps()
+		assert.Equal(t, "json", steps[0].PyString().MediaType(), name)
+		assert.Equal(t, []string{"{}"}, steps[0].PyString().Lines(), name)
+		assert.Equal(t, "", steps[1].PyString().MediaType(), name)
+	}
+}
+
+func TestParseFeatureFile(t *testing.T) {
+	feature, err := gherkin.ParseFeatureFile("calculator.feature.md", calculatorMarkdown)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Calculator", feature.Title())
+	}
+	feature, err = gherkin.ParseFeatureFile("calculator.feature", "Feature: Calculator\n")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Calculator", feature.Title())
+	}
+	for _, name := range []string{"empty.feature", "empty.feature.md"} {
+		feature, err = gherkin.ParseFeatureFile(name, "\n")
+		assert.Equal(t, gherkin.ErrNoFeature, err, name)
+		assert.Nil(t, feature, name)
+	}
+	_, err = gherkin.ParseFeatureFile("calculator.feature", calculatorMarkdown)
+	assert.IsType(t, &gherkin.ParseError{}, err)
+}
@@ -0,0 +1,89 @@
+package gherkin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/muhqu/go-gherkin/events"
+)
+
+// The events are serialized as NDJSON, one event per line, like:
+//
+//	{"type":"StepEvent","event":{"StepType":"Given","Text":"a step","Pos":{"Offset":24,"Line":3,"Column":5}}}
+type ndjsonEvent struct {
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+var ndjsonEventTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, e := range []GherkinEvent{
+		&events.FeatureEvent{}, &events.FeatureEndEvent{},
+		&events.BackgroundEvent{}, &events.BackgroundEndEvent{},
+		&events.ScenarioEvent{}, &events.ScenarioEndEvent{},
+		&events.OutlineEvent{}, &events.OutlineEndEvent{},
+		&events.OutlineExamplesEvent{}, &events.OutlineExamplesEndEvent{},
+		&events.StepEvent{}, &events.StepEndEvent{},
+		&events.PyStringEvent{}, &events.PyStringLineEvent{}, &events.PyStringEndEvent{},
+		&events.TableEvent{}, &events.TableRowEvent{}, &events.TableCellEvent{},
+		&events.TableRowEndEvent{}, &events.TableEndEvent{},
+		&events.BlankLineEvent{}, &events.CommentEvent{},
+	} {
+		t := reflect.TypeOf(e).Elem()
+		ndjsonEventTypes[t.Name()] = t
+	}
+}
+
+// NewNDJSONEventWriter returns an EventProcessor writing the events to w as
+// NDJSON, e.g. for golden files of the parser's output. Write errors stop the
+// parser. See ReadNDJSONEvents.
+func NewNDJSONEventWriter(w io.Writer) EventErrorProcessor {
+	return &ndjsonEventWriter{enc: json.NewEncoder(w)}
+}
+
+type ndjsonEventWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonEventWriter) ProcessEvent(e GherkinEvent) {
+	w.ProcessEventError(e)
+}
+
+func (w *ndjsonEventWriter) ProcessEventError(e GherkinEvent) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return w.enc.Encode(&ndjsonEvent{Type: reflect.TypeOf(e).Elem().Name(), Event: data})
+}
+
+// ReadNDJSONEvents reads the events written by an NDJSON event writer, to be
+// replayed with ReplayEvents. Blank lines are skipped.
+func ReadNDJSONEvents(r io.Reader) ([]GherkinEvent, error) {
+	var result []GherkinEvent
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var line ndjsonEvent
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNo, err)
+		}
+		t, ok := ndjsonEventTypes[line.Type]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown event type %q", lineNo, line.Type)
+		}
+		e := reflect.New(t).Interface().(GherkinEvent)
+		if err := json.Unmarshal(line.Event, e); err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNo, err)
+		}
+		result = append(result, e)
+	}
+	return result, scanner.Err()
+}
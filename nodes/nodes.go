@@ -1,6 +1,10 @@
 // Sub-Package gherkin/nodes provides the data-structure types for the gherkin DOM parser.
 package nodes
 
+import (
+	"github.com/muhqu/go-gherkin/events"
+)
+
 type NodeType int
 
 const (
@@ -42,16 +46,65 @@ func (nt NodeType) String() string {
 	return "Unknown"
 }
 
+// KeywordType is the effective type of a step. Given steps set up a context,
+// When steps perform an action and Then steps check the outcome. And, But, Or
+// and * steps continue the type of the step they follow, which includes the
+// steps of the background for the first steps of a scenario.
+type KeywordType int
+
+const (
+	UnknownKeywordType KeywordType = iota // And, But, Or or * not following any step
+	ContextKeywordType
+	ActionKeywordType
+	OutcomeKeywordType
+)
+
+func (kt KeywordType) String() string {
+	switch kt {
+	case ContextKeywordType:
+		return "Context"
+	case ActionKeywordType:
+		return "Action"
+	case OutcomeKeywordType:
+		return "Outcome"
+	}
+	return "Unknown"
+}
+
+// StepKeywordType returns the type of the step keyword stepType, which is
+// UnknownKeywordType for the conjunctions And, But, Or and *.
+func StepKeywordType(stepType string) KeywordType {
+	switch stepType {
+	case "Given":
+		return ContextKeywordType
+	case "When":
+		return ActionKeywordType
+	case "Then":
+		return OutcomeKeywordType
+	}
+	return UnknownKeywordType
+}
+
 type NodeInterface interface {
 	NodeType() NodeType
+	// Position within the parsed content, the zero Position for nodes that
+	// were not created by the parser.
+	Position() events.Position
 }
 type abstractNode struct {
 	nodeType NodeType
+	position events.Position
 }
 
 func (a *abstractNode) NodeType() NodeType {
 	return a.nodeType
 }
+func (a *abstractNode) Position() events.Position {
+	return a.position
+}
+func (a *abstractNode) SetPosition(position events.Position) {
+	a.position = position
+}
 
 // ----------------------------------------
 
@@ -69,6 +122,7 @@ type ScenarioNode interface {
 	Description() string
 	Steps() []StepNode
 	Tags() []string
+	TagPositions() []events.Position
 	Comment() CommentNode
 	Lines() []NodeInterface // StepNode | BlankLineNode
 }
@@ -80,6 +134,8 @@ type MutableScenarioNode interface {
 	AddBlankLine(line BlankLineNode)
 	SetComment(comment CommentNode)
 	SetDescription(description string)
+	SetTagPositions(positions []events.Position)
+	SetPosition(position events.Position)
 }
 
 type abstractScenarioNode struct {
@@ -90,6 +146,7 @@ type abstractScenarioNode struct {
 	steps       []StepNode
 	lines       []NodeInterface
 	tags        []string
+	tagPos      []events.Position
 	comment     CommentNode
 }
 
@@ -108,6 +165,12 @@ func (a *abstractScenarioNode) Lines() []NodeInterface {
 func (a *abstractScenarioNode) Tags() []string {
 	return a.tags
 }
+func (a *abstractScenarioNode) TagPositions() []events.Position {
+	return a.tagPos
+}
+func (a *abstractScenarioNode) SetTagPositions(positions []events.Position) {
+	a.tagPos = positions
+}
 func (a *abstractScenarioNode) Comment() CommentNode {
 	return a.comment
 }
@@ -144,6 +207,7 @@ type FeatureNode interface {
 	Background() BackgroundNode
 	Scenarios() []ScenarioNode
 	Tags() []string
+	TagPositions() []events.Position
 	Comment() CommentNode
 }
 
@@ -153,6 +217,8 @@ type MutableFeatureNode interface {
 	SetBackground(background BackgroundNode)
 	AddScenario(scenario ScenarioNode)
 	SetComment(comment CommentNode)
+	SetTagPositions(positions []events.Position)
+	SetPosition(position events.Position)
 }
 
 func NewMutableFeatureNode(title, description string, tags []string) MutableFeatureNode {
@@ -172,6 +238,7 @@ type featureNode struct {
 	background  BackgroundNode
 	scenarios   []ScenarioNode
 	tags        []string
+	tagPos      []events.Position
 	comment     CommentNode
 }
 
@@ -196,6 +263,12 @@ func (f *featureNode) Description() string {
 func (f *featureNode) Tags() []string {
 	return f.tags
 }
+func (f *featureNode) TagPositions() []events.Position {
+	return f.tagPos
+}
+func (f *featureNode) SetTagPositions(positions []events.Position) {
+	f.tagPos = positions
+}
 func (f *featureNode) Background() BackgroundNode {
 	if n := f.background; n != nil {
 		return n
@@ -260,7 +333,9 @@ type StepNode interface {
 	NodeInterface // NodeType: StepNodeType
 
 	StepType() string // Given, When, Then, And, Or, But
+	KeywordType() KeywordType
 	Text() string
+	TextPosition() events.Position // of Text, the zero Position if not known
 	PyString() PyStringNode
 	Table() TableNode
 	Comment() CommentNode
@@ -271,6 +346,7 @@ type MutableStepNode interface {
 	WithStepType(string) MutableStepNode
 	WithText(string) MutableStepNode
 	SetStepType(string)
+	SetKeywordType(KeywordType)
 	SetText(string)
 	WithPyString(PyStringNode) MutableStepNode
 	WithTable(TableNode) MutableStepNode
@@ -278,6 +354,8 @@ type MutableStepNode interface {
 	SetPyString(PyStringNode)
 	SetTable(TableNode)
 	SetComment(CommentNode)
+	SetPosition(events.Position)
+	SetTextPosition(events.Position)
 }
 
 func NewMutableStepNode(stepType, text string) MutableStepNode {
@@ -291,19 +369,36 @@ func NewMutableStepNode(stepType, text string) MutableStepNode {
 type stepNode struct {
 	abstractNode
 
-	stepType string
-	text     string
-	pyString PyStringNode
-	table    TableNode
-	comment  CommentNode
+	stepType    string
+	keywordType KeywordType
+	text        string
+	pyString    PyStringNode
+	table       TableNode
+	comment     CommentNode
+	textPos     events.Position
 }
 
+// SetStepType sets the keyword type too, unless stepType is a conjunction,
+// which continues the type the step had.
 func (s *stepNode) SetStepType(stepType string) {
 	s.stepType = stepType
+	if kt := StepKeywordType(stepType); kt != UnknownKeywordType {
+		s.keywordType = kt
+	}
+}
+func (s *stepNode) SetKeywordType(keywordType KeywordType) {
+	s.keywordType = keywordType
 }
 func (s *stepNode) SetText(text string) {
 	s.text = text
 }
+func (s *stepNode) TextPosition() events.Position {
+	return s.textPos
+}
+func (s *stepNode) SetTextPosition(position events.Position) {
+	s.textPos = position
+}
+
 func (s *stepNode) WithStepType(stepType string) MutableStepNode {
 	s.SetStepType(stepType)
 	return s
@@ -339,6 +434,9 @@ func (s *stepNode) SetComment(comment CommentNode) {
 func (s *stepNode) StepType() string {
 	return s.stepType
 }
+func (s *stepNode) KeywordType() KeywordType {
+	return s.keywordType
+}
 func (s *stepNode) Text() string {
 	return s.text
 }
@@ -368,6 +466,7 @@ type OutlineNode interface {
 
 	Examples() OutlineExamplesNode
 	AllExamples() []OutlineExamplesNode
+	TitlePosition() events.Position // of Title, the zero Position if not known
 }
 type MutableOutlineNode interface {
 	OutlineNode
@@ -380,6 +479,9 @@ type MutableOutlineNode interface {
 	SetExamples(examples OutlineExamplesNode)
 	AddExamples(examples OutlineExamplesNode)
 	SetComment(comment CommentNode)
+	SetTagPositions(positions []events.Position)
+	SetPosition(position events.Position)
+	SetTitlePosition(position events.Position)
 }
 
 type OutlineExamplesNodes []OutlineExamplesNode
@@ -391,8 +493,13 @@ func (o OutlineExamplesNodes) NodeType() NodeType {
 func (o OutlineExamplesNodes) Table() TableNode {
 	t := &tableNode{}
 	t.nodeType = TableNodeType
+	if len(o) > 0 && o[0].Table() != nil {
+		t.position = o[0].Table().Position()
+	}
 	for _, example := range o {
 		t.rows = append(t.rows, example.Table().Rows()...)
+		t.rowPos = append(t.rowPos, example.Table().RowPositions()...)
+		t.cellPos = append(t.cellPos, example.Table().CellPositions()...)
 	}
 	return t
 }
@@ -411,6 +518,13 @@ func (o OutlineExamplesNodes) Comment() CommentNode {
 	return nil
 }
 
+func (o OutlineExamplesNodes) Position() events.Position {
+	if len(o) > 0 {
+		return o[0].Position()
+	}
+	return events.Position{}
+}
+
 func NewMutableOutlineNode(title string, tags []string) MutableOutlineNode {
 	n := &outlineNode{}
 	n.nodeType = OutlineNodeType
@@ -423,6 +537,15 @@ type outlineNode struct {
 	abstractScenarioNode
 
 	examples OutlineExamplesNodes
+	titlePos events.Position
+}
+
+func (o *outlineNode) TitlePosition() events.Position {
+	return o.titlePos
+}
+
+func (o *outlineNode) SetTitlePosition(position events.Position) {
+	o.titlePos = position
 }
 
 func (o *outlineNode) SetExamples(examples OutlineExamplesNode) {
@@ -457,6 +580,7 @@ type MutableOutlineExamplesNode interface {
 	SetTitle(title string)
 	SetTable(table TableNode)
 	SetComment(comment CommentNode)
+	SetPosition(position events.Position)
 }
 
 func NewMutableOutlineExamplesNode(title string) *outlineExamplesNode {
@@ -515,6 +639,8 @@ type PyStringNode interface {
 
 	Lines() []string
 	String() string
+	LinePositions() []events.Position // of each line, behind the indentation removed
+	MediaType() string                // following the opening """, like "json", if any
 }
 
 type MutablePyStringNode interface {
@@ -522,6 +648,9 @@ type MutablePyStringNode interface {
 
 	AddLine(line string)
 	WithLines(lines []string) MutablePyStringNode
+	SetPosition(position events.Position)
+	SetLinePosition(position events.Position) // of the line added last, if any
+	SetMediaType(mediaType string)
 }
 
 func NewMutablePyStringNode() MutablePyStringNode {
@@ -533,21 +662,41 @@ func NewMutablePyStringNode() MutablePyStringNode {
 type pyStringNode struct {
 	abstractNode
 
-	lines []string
+	lines     []string
+	linePos   []events.Position
+	mediaType string
 }
 
 func (p *pyStringNode) AddLine(line string) {
 	p.lines = append(p.lines, line)
+	p.linePos = append(p.linePos, events.Position{})
 }
 func (p *pyStringNode) WithLines(lines []string) MutablePyStringNode {
 	p.lines = lines
+	p.linePos = make([]events.Position, len(lines))
 	return p
 }
 
+func (p *pyStringNode) LinePositions() []events.Position {
+	return p.linePos
+}
+func (p *pyStringNode) SetLinePosition(position events.Position) {
+	if len(p.linePos) > 0 {
+		p.linePos[len(p.linePos)-1] = position
+	}
+}
+
 func (p *pyStringNode) Lines() []string {
 	return p.lines
 }
 
+func (p *pyStringNode) MediaType() string {
+	return p.mediaType
+}
+func (p *pyStringNode) SetMediaType(mediaType string) {
+	p.mediaType = mediaType
+}
+
 func (p *pyStringNode) String() string {
 	s := ""
 	for _, line := range p.lines {
@@ -556,6 +705,15 @@ func (p *pyStringNode) String() string {
 	return s
 }
 
+// LinePosition returns the position of line i of pyString, or the zero
+// Position if the PyString has none for it.
+func LinePosition(pyString PyStringNode, i int) events.Position {
+	if positions := pyString.LinePositions(); i >= 0 && i < len(positions) {
+		return positions[i]
+	}
+	return events.Position{}
+}
+
 // ----------------------------------------
 
 type TableNode interface {
@@ -563,6 +721,8 @@ type TableNode interface {
 
 	Rows() [][]string
 	RowComments() []CommentNode
+	RowPositions() []events.Position    // position of the leading '|' of each row
+	CellPositions() [][]events.Position // position of the content of each cell
 }
 type MutableTableNode interface {
 	TableNode
@@ -572,6 +732,9 @@ type MutableTableNode interface {
 	AddRow(row []string)
 	AddCell(cell string)
 	SetRowComment(comment CommentNode)
+	SetRowPosition(position events.Position)
+	SetCellPosition(position events.Position)
+	SetPosition(position events.Position)
 }
 
 type tableNode struct {
@@ -580,6 +743,8 @@ type tableNode struct {
 	nextRowIndex int
 	comments     []CommentNode
 	rows         [][]string
+	rowPos       []events.Position
+	cellPos      [][]events.Position
 }
 
 func NewMutableTableNode() MutableTableNode {
@@ -593,6 +758,11 @@ func (t *tableNode) WithRows(rows [][]string) MutableTableNode {
 	t.rows = rows
 	t.comments = make([]CommentNode, len(rows)+1)
 	t.nextRowIndex = len(rows)
+	t.rowPos = make([]events.Position, len(rows))
+	t.cellPos = make([][]events.Position, len(rows))
+	for i, row := range rows {
+		t.cellPos[i] = make([]events.Position, len(row))
+	}
 	return t
 }
 func (t *tableNode) NewRow() {
@@ -602,10 +772,13 @@ func (t *tableNode) AddRow(row []string) {
 	t.nextRowIndex = t.nextRowIndex + 1
 	t.rows = append(t.rows, row)
 	t.comments = append(t.comments, nil)
+	t.rowPos = append(t.rowPos, events.Position{})
+	t.cellPos = append(t.cellPos, make([]events.Position, len(row)))
 }
 func (t *tableNode) AddCell(cell string) {
 	i := len(t.rows) - 1
 	t.rows[i] = append(t.rows[i], cell)
+	t.cellPos[i] = append(t.cellPos[i], events.Position{})
 }
 func (t *tableNode) RowComments() []CommentNode {
 	return t.comments
@@ -614,10 +787,48 @@ func (t *tableNode) SetRowComment(comment CommentNode) {
 	t.comments[t.nextRowIndex-1] = comment
 }
 
+func (t *tableNode) RowPositions() []events.Position {
+	return t.rowPos
+}
+func (t *tableNode) SetRowPosition(position events.Position) {
+	if len(t.rowPos) > 0 {
+		t.rowPos[len(t.rowPos)-1] = position
+	}
+}
+func (t *tableNode) CellPositions() [][]events.Position {
+	return t.cellPos
+}
+func (t *tableNode) SetCellPosition(position events.Position) {
+	if len(t.cellPos) == 0 {
+		return
+	}
+	if row := t.cellPos[len(t.cellPos)-1]; len(row) > 0 {
+		row[len(row)-1] = position
+	}
+}
+
 func (t *tableNode) Rows() [][]string {
 	return t.rows
 }
 
+// RowPosition returns the position of row i of table, or the zero Position if
+// the table has none for it, like tables not created by the parser may.
+func RowPosition(table TableNode, i int) events.Position {
+	if positions := table.RowPositions(); i >= 0 && i < len(positions) {
+		return positions[i]
+	}
+	return events.Position{}
+}
+
+// CellPosition returns the position of cell j of row i of table, or the zero
+// Position if the table has none for it.
+func CellPosition(table TableNode, i, j int) events.Position {
+	if positions := table.CellPositions(); i >= 0 && i < len(positions) && j >= 0 && j < len(positions[i]) {
+		return positions[i][j]
+	}
+	return events.Position{}
+}
+
 // ----------------------------------------
 
 type BlankLineNode interface {
@@ -629,6 +840,7 @@ type MutableBlankLineNode interface {
 	BlankLineNode
 
 	SetComment(comment CommentNode)
+	SetPosition(position events.Position)
 }
 
 type blankLineNode struct {
@@ -0,0 +1,154 @@
+// Sub-Package gherkin/pickles expands a parsed feature into the scenarios that
+// actually get executed, one per scenario and one per examples row of each
+// scenario outline, with the background steps prepended. CheckOutline finds
+// placeholders and examples that do not fit together.
+package pickles
+
+import (
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+// Pickle is a single executable scenario.
+type Pickle struct {
+	Name     string   // scenario title, with outline placeholders substituted
+	Tags     []string // feature tags followed by scenario tags
+	Feature  nodes.FeatureNode
+	Scenario nodes.ScenarioNode
+	Steps    []*Step
+
+	// set for pickles of scenario outlines only
+	Examples nodes.OutlineExamplesNode
+	Row      int // index into Examples.Table().Rows(), the header being 0
+}
+
+// Header returns the header row of the examples table, nil for plain scenarios.
+func (p *Pickle) Header() []string {
+	if p.Examples == nil {
+		return nil
+	}
+	return p.Examples.Table().Rows()[0]
+}
+
+// Values returns the examples row the pickle was expanded with, nil for
+// plain scenarios.
+func (p *Pickle) Values() []string {
+	if p.Examples == nil {
+		return nil
+	}
+	return p.Examples.Table().Rows()[p.Row]
+}
+
+// Step of a pickle. Text and arguments have outline placeholders substituted.
+type Step struct {
+	Node       nodes.StepNode
+	Background bool
+	Text       string
+	Table      nodes.TableNode
+	PyString   nodes.PyStringNode
+}
+
+// Compile returns the pickles of feature in document order.
+func Compile(feature nodes.FeatureNode) []*Pickle {
+	var pickles []*Pickle
+	for _, scenario := range feature.Scenarios() {
+		tags := append(append([]string{}, feature.Tags()...), scenario.Tags()...)
+		outline, ok := scenario.(nodes.OutlineNode)
+		if !ok {
+			p := &Pickle{
+				Name:     scenario.Title(),
+				Tags:     tags,
+				Feature:  feature,
+				Scenario: scenario,
+			}
+			p.Steps = compileSteps(feature, scenario, nil, nil)
+			pickles = append(pickles, p)
+			continue
+		}
+		for _, examples := range outline.AllExamples() {
+			if examples.Table() == nil {
+				continue
+			}
+			rows := examples.Table().Rows()
+			for i := 1; i < len(rows); i++ {
+				p := &Pickle{
+					Name:     Substitute(scenario.Title(), rows[0], rows[i]),
+					Tags:     tags,
+					Feature:  feature,
+					Scenario: scenario,
+					Examples: examples,
+					Row:      i,
+				}
+				p.Steps = compileSteps(feature, scenario, rows[0], rows[i])
+				pickles = append(pickles, p)
+			}
+		}
+	}
+	return pickles
+}
+
+func compileSteps(feature nodes.FeatureNode, scenario nodes.ScenarioNode, header, values []string) []*Step {
+	var steps []*Step
+	if background := feature.Background(); background != nil {
+		for _, step := range background.Steps() {
+			steps = append(steps, compileStep(step, true, nil, nil))
+		}
+	}
+	for _, step := range scenario.Steps() {
+		steps = append(steps, compileStep(step, false, header, values))
+	}
+	return steps
+}
+
+func compileStep(step nodes.StepNode, background bool, header, values []string) *Step {
+	s := &Step{
+		Node:       step,
+		Background: background,
+		Text:       Substitute(step.Text(), header, values),
+		Table:      step.Table(),
+		PyString:   step.PyString(),
+	}
+	if header == nil {
+		return s
+	}
+	if t := step.Table(); t != nil {
+		table := nodes.NewMutableTableNode()
+		table.SetPosition(t.Position())
+		for i, row := range t.Rows() {
+			table.NewRow()
+			table.SetRowPosition(nodes.RowPosition(t, i))
+			for j, cell := range row {
+				table.AddCell(Substitute(cell, header, values))
+				table.SetCellPosition(nodes.CellPosition(t, i, j))
+			}
+		}
+		s.Table = table
+	}
+	if p := step.PyString(); p != nil {
+		var lines []string
+		for _, line := range p.Lines() {
+			lines = append(lines, Substitute(line, header, values))
+		}
+		pyString := nodes.NewMutablePyStringNode().WithLines(lines)
+		pyString.SetPosition(p.Position())
+		pyString.SetMediaType(p.MediaType())
+		s.PyString = pyString
+	}
+	return s
+}
+
+// Substitute replaces the <placeholders> named in header with values. Values
+// containing placeholders are not substituted again.
+func Substitute(text string, header, values []string) string {
+	columns := make(map[string]string, len(header))
+	for i, name := range header {
+		if _, ok := columns[name]; !ok && i < len(values) {
+			columns[name] = values[i]
+		}
+	}
+	return placeholderRe.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, ok := columns[placeholder[1:len(placeholder)-1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
@@ -0,0 +1,126 @@
+package pickles_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/pickles"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleCompile() {
+	feature, _ := gherkin.ParseGherkinFeature(`
+@calc
+Feature: Dead Simple Calculator
+
+  Background:
+    Given a Simple Calculator
+
+  Scenario: Adding 2 numbers
+     When I add 2 and 2
+     Then the result should be 4
+
+  @math
+  Scenario Outline: Adding <left> and <right>
+     When I add <left> and <right>
+     Then the result should be <result>
+
+    Examples: Small numbers
+     | left | right | result |
+     | 2    | 2     | 4      |
+     | 3    | 4     | 7      |
+`)
+
+	for _, pickle := range pickles.Compile(feature) {
+		fmt.Printf("%s %v\n", pickle.Name, pickle.Tags)
+		for _, step := range pickle.Steps {
+			fmt.Printf("  %s %s\n", step.Node.StepType(), step.Text)
+		}
+	}
+
+	// Output:
+	// Adding 2 numbers [calc]
+	//   Given a Simple Calculator
+	//   When I add 2 and 2
+	//   Then the result should be 4
+	// Adding 2 and 2 [calc math]
+	//   Given a Simple Calculator
+	//   When I add 2 and 2
+	//   Then the result should be 4
+	// Adding 3 and 4 [calc math]
+	//   Given a Simple Calculator
+	//   When I add 3 and 4
+	//   Then the result should be 7
+}
+
+func TestCompileSubstitutesArguments(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature(`
+Feature: Withdrawal
+  Scenario Outline: Withdraw
+    Given my account has:
+      | balance   |
+      | <Balance> |
+    When I write a letter:
+      """
+      Please send me <Amount>
+      """
+
+    Examples:
+      | Balance | Amount |
+      | $500    | $50    |
+
+    Examples: Empty
+      | Balance | Amount |
+`)
+	assert.NoError(t, err)
+
+	list := pickles.Compile(feature)
+	assert.Len(t, list, 1)
+	p := list[0]
+	assert.Equal(t, 1, p.Row)
+	assert.Equal(t, []string{"Balance", "Amount"}, p.Header())
+	assert.Equal(t, []string{"$500", "$50"}, p.Values())
+	assert.Equal(t, [][]string{{"balance"}, {"$500"}}, p.Steps[0].Table.Rows())
+	assert.Equal(t, "5:7", p.Steps[0].Table.Position().String())
+	assert.Equal(t, []string{"Please send me $50"}, p.Steps[1].PyString.Lines())
+
+	outline := feature.Scenarios()[0]
+	assert.Equal(t, [][]string{{"balance"}, {"<Balance>"}}, outline.Steps()[0].Table().Rows())
+}
+
+func TestSubstitute(t *testing.T) {
+	assert.Equal(t, "<b> and x", pickles.Substitute("<a> and <b>", []string{"a", "b"}, []string{"<b>", "x"}))
+	assert.Equal(t, "1 <c> 1", pickles.Substitute("<a> <c> <a>", []string{"a", "a"}, []string{"1", "2"}))
+	assert.Equal(t, "<b>", pickles.Substitute("<b>", []string{"a", "b"}, []string{"1"}))
+}
+
+// positionlessTable is a TableNode implementation without positions.
+type positionlessTable struct {
+	nodes.TableNode
+}
+
+func (positionlessTable) RowPositions() []events.Position    { return nil }
+func (positionlessTable) CellPositions() [][]events.Position { return nil }
+
+func TestCompileTableWithoutPositions(t *testing.T) {
+	table := positionlessTable{nodes.NewMutableTableNode().WithRows([][]string{{"a"}, {"1"}, {"2", "3"}})}
+	outline := nodes.NewMutableOutlineNode("<a> <b>", nil)
+	stepTable := positionlessTable{nodes.NewMutableTableNode().WithRows([][]string{{"<a>"}, {"x", "<a>"}})}
+	outline.AddStep(nodes.NewMutableStepNode("Given", "<a>").WithTable(stepTable))
+	outline.SetExamples(nodes.NewOutlineExamplesNode(table))
+	feature := nodes.NewMutableFeatureNode("F", "", nil)
+	feature.AddScenario(outline)
+
+	list := pickles.Compile(feature)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, [][]string{{"1"}, {"x", "1"}}, list[0].Steps[0].Table.Rows())
+		assert.Equal(t, events.Position{}, list[0].Steps[0].Table.RowPositions()[0])
+	}
+	report := pickles.CheckOutline(outline)
+	assert.Len(t, report.Unknown, 1)
+	assert.Len(t, report.BadRows, 1)
+	assert.Equal(t, events.Position{}, report.BadRows[0].Pos)
+}
@@ -0,0 +1,122 @@
+package pickles
+
+import (
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+var placeholderRe = regexp.MustCompile(`<([^<>]+)>`)
+
+// Placeholder is a <name> used by a scenario outline.
+type Placeholder struct {
+	Name string
+	Node nodes.NodeInterface // the outline, step, table or PyString using it
+	// Pos is that of the '<', or the position of Node if the parser did not
+	// record the position of the text holding it.
+	Pos events.Position
+}
+
+// Placeholders returns the placeholders used by outline, in its title and in
+// the texts, tables and PyStrings of its steps, in document order.
+func Placeholders(outline nodes.OutlineNode) []*Placeholder {
+	var result []*Placeholder
+	add := func(text string, node nodes.NodeInterface, pos events.Position) {
+		for _, m := range placeholderRe.FindAllStringSubmatchIndex(text, -1) {
+			p := &Placeholder{Name: text[m[2]:m[3]], Node: node, Pos: node.Position()}
+			if pos.IsValid() {
+				p.Pos = pos
+				p.Pos.Offset += m[0]
+				p.Pos.Column += utf8.RuneCountInString(text[:m[0]])
+			}
+			result = append(result, p)
+		}
+	}
+	add(outline.Title(), outline, outline.TitlePosition())
+	for _, step := range outline.Steps() {
+		add(step.Text(), step, step.TextPosition())
+		if table := step.Table(); table != nil {
+			for i, row := range table.Rows() {
+				for j, cell := range row {
+					add(cell, table, nodes.CellPosition(table, i, j))
+				}
+			}
+		}
+		if pyString := step.PyString(); pyString != nil {
+			for i, line := range pyString.Lines() {
+				add(line, pyString, nodes.LinePosition(pyString, i))
+			}
+		}
+	}
+	return result
+}
+
+// OutlineReport tells how the placeholders of an outline and the columns of
+// its examples fit together. Examples without table are skipped, as they do
+// not produce any pickles.
+type OutlineReport struct {
+	Placeholders []*Placeholder
+	Unknown      []*UnknownPlaceholder // not a column of the examples
+	Unused       []*ExamplesColumn     // not used as placeholder
+	BadRows      []*ExamplesRow        // with more or less cells than the header
+}
+
+type UnknownPlaceholder struct {
+	*Placeholder
+	Examples nodes.OutlineExamplesNode
+}
+
+type ExamplesColumn struct {
+	Examples nodes.OutlineExamplesNode
+	Name     string
+	Pos      events.Position // of the header cell
+}
+
+type ExamplesRow struct {
+	Examples nodes.OutlineExamplesNode
+	Row      int // index into Examples.Table().Rows(), the header being 0
+	Cells    int
+	Pos      events.Position // of the leading '|'
+}
+
+// OK reports whether the report found nothing wrong.
+func (r *OutlineReport) OK() bool {
+	return len(r.Unknown) == 0 && len(r.Unused) == 0 && len(r.BadRows) == 0
+}
+
+// CheckOutline compares the placeholders of outline with the header row of
+// each of its examples.
+func CheckOutline(outline nodes.OutlineNode) *OutlineReport {
+	r := &OutlineReport{Placeholders: Placeholders(outline)}
+	used := make(map[string]bool)
+	for _, p := range r.Placeholders {
+		used[p.Name] = true
+	}
+	for _, examples := range outline.AllExamples() {
+		table := examples.Table()
+		if table == nil || len(table.Rows()) == 0 {
+			continue
+		}
+		rows := table.Rows()
+		columns := make(map[string]bool)
+		for i, name := range rows[0] {
+			columns[name] = true
+			if !used[name] {
+				r.Unused = append(r.Unused, &ExamplesColumn{Examples: examples, Name: name, Pos: nodes.CellPosition(table, 0, i)})
+			}
+		}
+		for _, p := range r.Placeholders {
+			if !columns[p.Name] {
+				r.Unknown = append(r.Unknown, &UnknownPlaceholder{Placeholder: p, Examples: examples})
+			}
+		}
+		for i := 1; i < len(rows); i++ {
+			if len(rows[i]) != len(rows[0]) {
+				r.BadRows = append(r.BadRows, &ExamplesRow{Examples: examples, Row: i, Cells: len(rows[i]), Pos: nodes.RowPosition(table, i)})
+			}
+		}
+	}
+	return r
+}
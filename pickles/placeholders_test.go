@@ -0,0 +1,75 @@
+package pickles_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/pickles"
+	"github.com/stretchr/testify/assert"
+)
+
+const withdrawalOutline = `Feature: Account withdrawal
+
+  Scenario Outline: Withdraw <Withdrawal>
+    Given I have <Balanse> in my account
+    When I withdraw:
+      | amount       |
+      | <Withdrawal> |
+    Then I should see:
+      """
+      <Outcome>
+      """
+
+    Examples:
+      | Balance | Withdrawal | Outcome |
+      | $500    | $50        | cash    |
+      | $500    | $600       |
+
+    Examples: no table
+`
+
+func ExampleCheckOutline() {
+	feature, _ := gherkin.ParseGherkinFeature(withdrawalOutline)
+	report := pickles.CheckOutline(feature.Scenarios()[0].(nodes.OutlineNode))
+	for _, p := range report.Unknown {
+		fmt.Printf("%s: unknown placeholder <%s>\n", p.Pos, p.Name)
+	}
+	for _, c := range report.Unused {
+		fmt.Printf("%s: unused column %s\n", c.Pos, c.Name)
+	}
+	for _, r := range report.BadRows {
+		fmt.Printf("%s: row %d has %d cells\n", r.Pos, r.Row, r.Cells)
+	}
+
+	// Output:
+	// 4:18: unknown placeholder <Balanse>
+	// 14:9: unused column Balance
+	// 16:7: row 2 has 2 cells
+}
+
+func TestPlaceholders(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature(withdrawalOutline)
+	if !assert.NoError(t, err) {
+		return
+	}
+	outline := feature.Scenarios()[0].(nodes.OutlineNode)
+	var found []string
+	for _, p := range pickles.Placeholders(outline) {
+		found = append(found, fmt.Sprintf("%s@%s %s", p.Name, p.Pos, p.Node.NodeType()))
+	}
+	assert.Equal(t, []string{
+		"Withdrawal@3:30 Outline",
+		"Balanse@4:18 Step",
+		"Withdrawal@7:9 Table",
+		"Outcome@10:7 PyString",
+	}, found)
+	for i, text := range []string{"<Withdrawal>\n", "<Balanse>", "<Withdrawal> |", "<Outcome>"} {
+		assert.Equal(t, strings.Index(withdrawalOutline, text), pickles.Placeholders(outline)[i].Pos.Offset, text)
+	}
+
+	assert.True(t, pickles.CheckOutline(nodes.NewMutableOutlineNode("<x>", nil)).OK())
+	assert.False(t, pickles.CheckOutline(outline).OK())
+}
@@ -0,0 +1,109 @@
+package gherkin_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/stretchr/testify/assert"
+)
+
+var errForbiddenTag = errors.New("@wip is not allowed")
+
+func forbidWIP(e gherkin.GherkinEvent) error {
+	if s, ok := e.(*events.ScenarioEvent); ok {
+		for _, tag := range s.Tags {
+			if tag == "wip" {
+				return errForbiddenTag
+			}
+		}
+	}
+	return nil
+}
+
+const forbiddenTagFeature = `Feature: F
+  Scenario: Ok
+    Given a step
+
+  @wip
+  Scenario: Not ok
+    Given a step
+`
+
+func ExampleEventErrorProcessorFn() {
+	gp := gherkin.NewGherkinDOMParser(forbiddenTagFeature)
+	gp.WithEventProcessor(gherkin.EventErrorProcessorFn(forbidWIP))
+	_, err := gp.ParseDOM()
+	fmt.Println(err)
+
+	// Output:
+	// line 6: @wip is not allowed
+}
+
+func TestEventErrorProcessorStopsParsers(t *testing.T) {
+	parsers := map[string]func(gherkin.EventProcessor) error{
+		"peg": func(ep gherkin.EventProcessor) error {
+			gp := gherkin.NewGherkinParser(forbiddenTagFeature)
+			gp.WithEventProcessor(ep)
+			gp.Init()
+			if err := gp.Parse(); err != nil {
+				return err
+			}
+			return gp.Execute()
+		},
+		"dom": func(ep gherkin.EventProcessor) error {
+			gp := gherkin.NewGherkinDOMParser(forbiddenTagFeature)
+			gp.WithEventProcessor(ep)
+			_, err := gp.ParseDOM()
+			return err
+		},
+		"stream": func(ep gherkin.EventProcessor) error {
+			sp := gherkin.NewGherkinStreamParser(strings.NewReader(forbiddenTagFeature))
+			sp.WithEventProcessor(ep)
+			return sp.Parse()
+		},
+		"markdown": func(ep gherkin.EventProcessor) error {
+			gp := gherkin.NewMarkdownGherkinParser("# Feature: F\n## Scenario: Ok\n* Given a step\n\n`@wip`\n## Scenario: Not ok\n* Given a step\n")
+			gp.WithEventProcessor(ep)
+			gp.Init()
+			if err := gp.Parse(); err != nil {
+				return err
+			}
+			return gp.Execute()
+		},
+	}
+	for name, parse := range parsers {
+		var seen []string
+		err := parse(gherkin.EventErrorProcessorFn(func(e gherkin.GherkinEvent) error {
+			if err := forbidWIP(e); err != nil {
+				return err
+			}
+			seen = append(seen, fmt.Sprintf("%T", e))
+			return nil
+		}))
+		if e, ok := err.(*gherkin.EventError); assert.True(t, ok, name) {
+			assert.Equal(t, errForbiddenTag, e.Err, name)
+			assert.Equal(t, 6, e.Pos.Line, name)
+			assert.Equal(t, "Not ok", e.Event.(*events.ScenarioEvent).Title, name)
+		}
+		assert.Equal(t, "*events.ScenarioEndEvent", seen[len(seen)-1], name)
+	}
+}
+
+func TestEventErrorProcessorFnIsEventProcessor(t *testing.T) {
+	gp := gherkin.NewGherkinDOMParser(forbiddenTagFeature)
+	var count int
+	gp.WithEventProcessor(gherkin.EventProcessorFn(func(gherkin.GherkinEvent) { count++ }))
+	gp.WithEventProcessor(gherkin.EventErrorProcessorFn(forbidWIP))
+	gp.WithEventProcessor(gherkin.EventProcessorFn(func(gherkin.GherkinEvent) { count++ }))
+	_, err := gp.ParseDOM()
+	assert.Error(t, err)
+	// the scenario event reached the first processor only
+	assert.Equal(t, 2*6+1, count)
+
+	var plain gherkin.EventProcessor = gherkin.EventErrorProcessorFn(forbidWIP)
+	plain.ProcessEvent(&events.ScenarioEvent{Tags: []string{"wip"}})
+}
@@ -0,0 +1,96 @@
+package gherkin
+
+import (
+	"github.com/muhqu/go-gherkin/events"
+)
+
+// The processors of this file pass the errors of EventErrorProcessors on, so
+// that they stop the parser just like when registered directly.
+
+// FilterEvents returns an EventProcessor passing the events of the given
+// types on to ep, and dropping all others.
+func FilterEvents(ep EventProcessor, types ...events.EventType) EventErrorProcessor {
+	f := &eventFilter{ep: ep, types: make(map[events.EventType]bool, len(types))}
+	for _, t := range types {
+		f.types[t] = true
+	}
+	return f
+}
+
+type eventFilter struct {
+	ep    EventProcessor
+	types map[events.EventType]bool
+}
+
+func (f *eventFilter) ProcessEvent(e GherkinEvent) {
+	f.ProcessEventError(e)
+}
+
+func (f *eventFilter) ProcessEventError(e GherkinEvent) error {
+	if !f.types[e.EventType()] {
+		return nil
+	}
+	return processEventError(f.ep, e)
+}
+
+// TeeEvents returns an EventProcessor passing each event on to all of eps, in
+// order, up to the first one that fails.
+func TeeEvents(eps ...EventProcessor) EventErrorProcessor {
+	return eventTee(eps)
+}
+
+type eventTee []EventProcessor
+
+func (t eventTee) ProcessEvent(e GherkinEvent) {
+	t.ProcessEventError(e)
+}
+
+func (t eventTee) ProcessEventError(e GherkinEvent) error {
+	for _, ep := range t {
+		if err := processEventError(ep, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EventRecorder is an EventProcessor capturing the events, to be replayed
+// later.
+type EventRecorder interface {
+	EventProcessor
+	Events() []GherkinEvent
+	// Reset drops the events recorded so far.
+	Reset()
+}
+
+func NewEventRecorder() EventRecorder {
+	return &eventRecorder{}
+}
+
+type eventRecorder struct {
+	events []GherkinEvent
+}
+
+func (r *eventRecorder) ProcessEvent(e GherkinEvent) {
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) Events() []GherkinEvent {
+	return r.events
+}
+
+func (r *eventRecorder) Reset() {
+	r.events = nil
+}
+
+// ReplayEvents feeds the recorded events to the processors, as a parser would,
+// e.g. to a GherkinDOMBuilder. Like the parsers, it stops at the first
+// EventErrorProcessor that fails and returns an *EventError.
+func ReplayEvents(recorded []GherkinEvent, eps ...EventProcessor) error {
+	for _, e := range recorded {
+		if err := processEvent(eps, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
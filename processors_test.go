@@ -0,0 +1,95 @@
+package gherkin_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleNewNDJSONEventWriter() {
+	gp := gherkin.NewGherkinParser("Feature: F\n  Scenario: S\n    Given a step\n")
+	gp.WithEventProcessor(gherkin.FilterEvents(gherkin.NewNDJSONEventWriter(os.Stdout),
+		events.ScenarioEventType, events.StepEventType))
+	gp.Init()
+	gp.Parse()
+	gp.Execute()
+
+	// Output:
+	// {"type":"ScenarioEvent","event":{"Title":"S","Description":"","Tags":null,"TagPositions":null,"Pos":{"Offset":13,"Line":2,"Column":3}}}
+	// {"type":"StepEvent","event":{"StepType":"Given","Text":"a step","Pos":{"Offset":29,"Line":3,"Column":5},"TextPos":{"Offset":35,"Line":3,"Column":11}}}
+}
+
+func TestFilterAndTeeEvents(t *testing.T) {
+	steps, all := gherkin.NewEventRecorder(), gherkin.NewEventRecorder()
+	gp := gherkin.NewGherkinParser(calculatorGherkin)
+	gp.WithEventProcessor(gherkin.TeeEvents(all, gherkin.FilterEvents(steps, events.StepEventType)))
+	gp.Init()
+	assert.NoError(t, gp.Parse())
+	assert.NoError(t, gp.Execute())
+
+	assert.Len(t, steps.Events(), 6)
+	for _, e := range steps.Events() {
+		assert.IsType(t, &events.StepEvent{}, e)
+	}
+	assert.True(t, len(all.Events()) > 6)
+	all.Reset()
+	assert.Empty(t, all.Events())
+}
+
+func TestTeePassesErrorsOn(t *testing.T) {
+	failure := errors.New("stop")
+	rec := gherkin.NewEventRecorder()
+	sp := gherkin.NewGherkinStreamParser(strings.NewReader(calculatorGherkin))
+	sp.WithEventProcessor(gherkin.TeeEvents(
+		gherkin.FilterEvents(gherkin.EventErrorProcessorFn(func(gherkin.GherkinEvent) error { return failure }), events.StepEventType),
+		rec,
+	))
+	err := sp.Parse()
+	if e, ok := err.(*gherkin.EventError); assert.True(t, ok) {
+		assert.Equal(t, failure, e.Err)
+		assert.IsType(t, &events.StepEvent{}, e.Event)
+	}
+	// the failing step did not reach the recorder
+	assert.IsType(t, &events.BackgroundEvent{}, rec.Events()[len(rec.Events())-1])
+}
+
+func TestReplayEventsIntoDOMBuilder(t *testing.T) {
+	rec := gherkin.NewEventRecorder()
+	gp := gherkin.NewGherkinDOMParser(benchmarkGherkinText)
+	gp.WithEventProcessor(rec)
+	expected, err := gp.ParseFeature()
+	assert.NoError(t, err)
+
+	b := gherkin.NewGherkinDOMBuilder()
+	assert.NoError(t, gherkin.ReplayEvents(rec.Events(), b))
+	assert.Equal(t, expected, b.Feature())
+
+	assert.Nil(t, gherkin.NewGherkinDOMBuilder().Feature())
+}
+
+func TestNDJSONRoundTrip(t *testing.T) {
+	rec := gherkin.NewEventRecorder()
+	buf := new(bytes.Buffer)
+	gp := gherkin.NewGherkinParser(benchmarkGherkinText)
+	gp.WithEventProcessor(rec)
+	gp.WithEventProcessor(gherkin.NewNDJSONEventWriter(buf))
+	gp.Init()
+	assert.NoError(t, gp.Parse())
+	assert.NoError(t, gp.Execute())
+	assert.Equal(t, len(rec.Events()), strings.Count(buf.String(), "\n"))
+
+	read, err := gherkin.ReadNDJSONEvents(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, rec.Events(), read)
+
+	_, err = gherkin.ReadNDJSONEvents(strings.NewReader("\n{\"type\":\"NoEvent\",\"event\":{}}\n"))
+	assert.EqualError(t, err, `line 2: unknown event type "NoEvent"`)
+	_, err = gherkin.ReadNDJSONEvents(strings.NewReader("{"))
+	assert.Error(t, err)
+}
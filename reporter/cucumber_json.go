@@ -0,0 +1,208 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/pickles"
+)
+
+// CucumberJSONReporter writes results in the legacy Cucumber JSON format, as
+// read by cucumber-reporting, Xray and others.
+//
+// Every pickle becomes an element of its feature, so each examples row of a
+// scenario outline shows up separately. As in Cucumber, the background steps
+// are written as a "background" element preceding each scenario.
+type CucumberJSONReporter struct{}
+
+type cucumberFeature struct {
+	URI         string             `json:"uri"`
+	ID          string             `json:"id"`
+	Keyword     string             `json:"keyword"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Line        int                `json:"line"`
+	Tags        []*cucumberTag     `json:"tags,omitempty"`
+	Elements    []*cucumberElement `json:"elements"`
+}
+
+type cucumberElement struct {
+	ID          string          `json:"id,omitempty"`
+	Keyword     string          `json:"keyword"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Line        int             `json:"line"`
+	Type        string          `json:"type"`
+	Tags        []*cucumberTag  `json:"tags,omitempty"`
+	Steps       []*cucumberStep `json:"steps"`
+}
+
+type cucumberTag struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+type cucumberStep struct {
+	Keyword    string               `json:"keyword"`
+	Name       string               `json:"name"`
+	Line       int                  `json:"line"`
+	DocString  *cucumberDocString   `json:"doc_string,omitempty"`
+	Rows       []*cucumberRow       `json:"rows,omitempty"`
+	Result     *cucumberResult      `json:"result"`
+	Embeddings []*cucumberEmbedding `json:"embeddings,omitempty"`
+}
+
+type cucumberDocString struct {
+	ContentType string `json:"content_type,omitempty"`
+	Value       string `json:"value"`
+	Line        int    `json:"line"`
+}
+
+type cucumberRow struct {
+	Cells []string `json:"cells"`
+	Line  int      `json:"line,omitempty"`
+}
+
+type cucumberResult struct {
+	Status       string `json:"status"`
+	Duration     int64  `json:"duration,omitempty"` // nanoseconds
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+type cucumberEmbedding struct {
+	MimeType string `json:"mime_type"`
+	Data     []byte `json:"data"` // base64 encoded by encoding/json
+}
+
+func (r *CucumberJSONReporter) Report(features []*FeatureResult, out io.Writer) error {
+	doc := []*cucumberFeature{}
+	for _, feature := range features {
+		doc = append(doc, r.feature(feature))
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(b, '\n'))
+	return err
+}
+
+func (r *CucumberJSONReporter) feature(result *FeatureResult) *cucumberFeature {
+	feature := result.Feature
+	f := &cucumberFeature{
+		URI:         result.Path,
+		ID:          cucumberID(feature.Title()),
+		Keyword:     "Feature",
+		Name:        feature.Title(),
+		Description: feature.Description(),
+		Line:        feature.Position().Line,
+		Tags:        cucumberTags(feature.Tags(), feature.TagPositions()),
+		Elements:    []*cucumberElement{},
+	}
+	for _, scenario := range result.Scenarios {
+		background, steps := r.steps(scenario)
+		if bg := feature.Background(); bg != nil {
+			f.Elements = append(f.Elements, &cucumberElement{
+				Keyword:     "Background",
+				Name:        bg.Title(),
+				Description: bg.Description(),
+				Line:        bg.Position().Line,
+				Type:        "background",
+				Steps:       background,
+			})
+		}
+		f.Elements = append(f.Elements, r.element(f.ID, scenario.Pickle, steps))
+	}
+	return f
+}
+
+func (r *CucumberJSONReporter) element(featureID string, pickle *pickles.Pickle, steps []*cucumberStep) *cucumberElement {
+	scenario := pickle.Scenario
+	e := &cucumberElement{
+		ID:          featureID + ";" + cucumberID(scenario.Title()),
+		Keyword:     "Scenario",
+		Name:        pickle.Name,
+		Description: scenario.Description(),
+		Line:        scenario.Position().Line,
+		Type:        "scenario",
+		Steps:       steps,
+	}
+	e.Tags = append(cucumberTags(pickle.Feature.Tags(), pickle.Feature.TagPositions()),
+		cucumberTags(scenario.Tags(), scenario.TagPositions())...)
+	if pickle.Examples != nil {
+		// Cucumber counts the rows starting at 1 for the header
+		e.Keyword = "Scenario Outline"
+		e.ID += ";" + cucumberID(pickle.Examples.Title()) + ";" + strconv.Itoa(pickle.Row+1)
+		if rows := pickle.Examples.Table().RowPositions(); pickle.Row < len(rows) {
+			e.Line = rows[pickle.Row].Line
+		}
+	}
+	return e
+}
+
+// steps returns the background steps and the scenario steps of a scenario.
+func (r *CucumberJSONReporter) steps(scenario *ScenarioResult) (background, steps []*cucumberStep) {
+	background, steps = []*cucumberStep{}, []*cucumberStep{}
+	for _, result := range scenario.Steps {
+		step := result.Step
+		s := &cucumberStep{
+			Keyword: step.Node.StepType() + " ",
+			Name:    step.Text,
+			Line:    step.Node.Position().Line,
+			Result: &cucumberResult{
+				Status:       result.Status.String(),
+				Duration:     result.Duration.Nanoseconds(),
+				ErrorMessage: result.Error,
+			},
+		}
+		if step.PyString != nil {
+			s.DocString = &cucumberDocString{
+				ContentType: step.PyString.MediaType(),
+				Value:       strings.Join(step.PyString.Lines(), "\n"),
+				Line:        step.PyString.Position().Line,
+			}
+		}
+		if step.Table != nil {
+			for i, row := range step.Table.Rows() {
+				s.Rows = append(s.Rows, &cucumberRow{Cells: row, Line: nodes.RowPosition(step.Table, i).Line})
+			}
+		}
+		for _, embedding := range result.Embeddings {
+			s.Embeddings = append(s.Embeddings, &cucumberEmbedding{MimeType: embedding.MimeType, Data: embedding.Data})
+		}
+		if step.Background {
+			background = append(background, s)
+		} else {
+			steps = append(steps, s)
+		}
+	}
+	return background, steps
+}
+
+func cucumberTags(tags []string, positions []events.Position) []*cucumberTag {
+	var t []*cucumberTag
+	for i, tag := range tags {
+		ct := &cucumberTag{Name: "@" + tag}
+		if i < len(positions) {
+			ct.Line = positions[i].Line
+		}
+		t = append(t, ct)
+	}
+	return t
+}
+
+// cucumberID derives an id the way Cucumber does, by lower-casing the title
+// and replacing whitespace with dashes.
+func cucumberID(title string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return '-'
+		}
+		return unicode.ToLower(r)
+	}, title)
+}
@@ -0,0 +1,210 @@
+package reporter_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/reporter"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleCucumberJSONReporter() {
+	feature, _ := gherkin.ParseGherkinFeature(`@calc
+Feature: Calculator
+
+  Background:
+    Given a calculator
+
+  Scenario Outline: Adding
+     When I add <a> and <b>
+     Then I see:
+       """text/plain
+       <sum>
+       """
+
+    Examples: Small numbers
+     | a | b | sum |
+     | 1 | 2 | 3   |
+
+  @screenshot
+  Scenario: Keys
+     When I press the keys:
+       | key |
+       | 2   |
+`)
+	result := reporter.NewFeatureResult("features/calculator.feature", feature)
+	for _, scenario := range result.Scenarios {
+		for _, step := range scenario.Steps {
+			step.Status = reporter.StatusPassed
+			step.Duration = 1500 * time.Microsecond
+		}
+	}
+	keys := result.Scenarios[1].Steps[1]
+	keys.Status = reporter.StatusFailed
+	keys.Error = "key 2 is stuck"
+	keys.Embed("text/plain", []byte("2"))
+
+	r := &reporter.CucumberJSONReporter{}
+	r.Report([]*reporter.FeatureResult{result}, os.Stdout)
+
+	// Output:
+	// [
+	//   {
+	//     "uri": "features/calculator.feature",
+	//     "id": "calculator",
+	//     "keyword": "Feature",
+	//     "name": "Calculator",
+	//     "description": "",
+	//     "line": 2,
+	//     "tags": [
+	//       {
+	//         "name": "@calc",
+	//         "line": 1
+	//       }
+	//     ],
+	//     "elements": [
+	//       {
+	//         "keyword": "Background",
+	//         "name": "",
+	//         "description": "",
+	//         "line": 4,
+	//         "type": "background",
+	//         "steps": [
+	//           {
+	//             "keyword": "Given ",
+	//             "name": "a calculator",
+	//             "line": 5,
+	//             "result": {
+	//               "status": "passed",
+	//               "duration": 1500000
+	//             }
+	//           }
+	//         ]
+	//       },
+	//       {
+	//         "id": "calculator;adding;small-numbers;2",
+	//         "keyword": "Scenario Outline",
+	//         "name": "Adding",
+	//         "description": "",
+	//         "line": 16,
+	//         "type": "scenario",
+	//         "tags": [
+	//           {
+	//             "name": "@calc",
+	//             "line": 1
+	//           }
+	//         ],
+	//         "steps": [
+	//           {
+	//             "keyword": "When ",
+	//             "name": "I add 1 and 2",
+	//             "line": 8,
+	//             "result": {
+	//               "status": "passed",
+	//               "duration": 1500000
+	//             }
+	//           },
+	//           {
+	//             "keyword": "Then ",
+	//             "name": "I see:",
+	//             "line": 9,
+	//             "doc_string": {
+	//               "content_type": "text/plain",
+	//               "value": "3",
+	//               "line": 10
+	//             },
+	//             "result": {
+	//               "status": "passed",
+	//               "duration": 1500000
+	//             }
+	//           }
+	//         ]
+	//       },
+	//       {
+	//         "keyword": "Background",
+	//         "name": "",
+	//         "description": "",
+	//         "line": 4,
+	//         "type": "background",
+	//         "steps": [
+	//           {
+	//             "keyword": "Given ",
+	//             "name": "a calculator",
+	//             "line": 5,
+	//             "result": {
+	//               "status": "passed",
+	//               "duration": 1500000
+	//             }
+	//           }
+	//         ]
+	//       },
+	//       {
+	//         "id": "calculator;keys",
+	//         "keyword": "Scenario",
+	//         "name": "Keys",
+	//         "description": "",
+	//         "line": 19,
+	//         "type": "scenario",
+	//         "tags": [
+	//           {
+	//             "name": "@calc",
+	//             "line": 1
+	//           },
+	//           {
+	//             "name": "@screenshot",
+	//             "line": 18
+	//           }
+	//         ],
+	//         "steps": [
+	//           {
+	//             "keyword": "When ",
+	//             "name": "I press the keys:",
+	//             "line": 20,
+	//             "rows": [
+	//               {
+	//                 "cells": [
+	//                   "key"
+	//                 ],
+	//                 "line": 21
+	//               },
+	//               {
+	//                 "cells": [
+	//                   "2"
+	//                 ],
+	//                 "line": 22
+	//               }
+	//             ],
+	//             "result": {
+	//               "status": "failed",
+	//               "duration": 1500000,
+	//               "error_message": "key 2 is stuck"
+	//             },
+	//             "embeddings": [
+	//               {
+	//                 "mime_type": "text/plain",
+	//                 "data": "Mg=="
+	//               }
+	//             ]
+	//           }
+	//         ]
+	//       }
+	//     ]
+	//   }
+	// ]
+}
+
+func TestCucumberJSONEmptyBackground(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature("Feature: F\n  Background:\n\n  Scenario: S\n")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var buf bytes.Buffer
+	r := &reporter.CucumberJSONReporter{}
+	assert.NoError(t, r.Report([]*reporter.FeatureResult{reporter.NewFeatureResult("f.feature", feature)}, &buf))
+	assert.Equal(t, 2, strings.Count(buf.String(), `"steps": []`), buf.String())
+	assert.NotContains(t, buf.String(), "null")
+}
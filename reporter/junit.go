@@ -0,0 +1,159 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JUnitReporter writes results as JUnit XML, with one <testsuite> per
+// feature and one <testcase> per pickle.
+type JUnitReporter struct {
+	Name   string // name of the <testsuites>
+	Strict bool   // report pending, undefined and ambiguous steps as failures instead of skipped
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr,omitempty"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut *junitOutput  `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",cdata"`
+}
+
+type junitOutput struct {
+	Text string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+func (r *JUnitReporter) Report(features []*FeatureResult, out io.Writer) error {
+	suites := &junitTestSuites{Name: r.Name}
+	var total time.Duration
+	for _, feature := range features {
+		suite := r.testSuite(feature)
+		suites.Suites = append(suites.Suites, suite)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		total += feature.Duration()
+	}
+	suites.Time = fmtSeconds(total)
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(out, "\n")
+	return err
+}
+
+func (r *JUnitReporter) testSuite(feature *FeatureResult) *junitTestSuite {
+	name := feature.Feature.Title()
+	if name == "" {
+		name = feature.Path
+	}
+	suite := &junitTestSuite{Name: name, Time: fmtSeconds(feature.Duration())}
+	for _, scenario := range feature.Scenarios {
+		tc := &junitTestCase{
+			ClassName: name,
+			Name:      junitTestCaseName(scenario),
+			Time:      fmtSeconds(scenario.Duration()),
+		}
+		if scenario.Output != "" {
+			tc.SystemOut = &junitOutput{scenario.Output}
+		}
+		suite.Tests++
+		switch status := scenario.Status(); status {
+		case StatusPassed:
+		case StatusFailed:
+			tc.Failure = r.failure(scenario)
+		case StatusSkipped:
+			tc.Skipped = &junitSkipped{}
+		default:
+			if r.Strict {
+				tc.Failure = r.failure(scenario)
+			} else {
+				tc.Skipped = &junitSkipped{Message: status.String()}
+			}
+		}
+		if tc.Failure != nil {
+			suite.Failures++
+		} else if tc.Skipped != nil {
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return suite
+}
+
+// junitTestCaseName derives the name from the scenario title and, for
+// outlines, the examples title and row.
+//
+//	Simple Math (Additions #2: 3 | + | 4 | 7)
+func junitTestCaseName(scenario *ScenarioResult) string {
+	pickle := scenario.Pickle
+	if pickle.Examples == nil {
+		return pickle.Name
+	}
+	examples := pickle.Examples.Title()
+	if examples == "" {
+		examples = "Examples"
+	}
+	return fmt.Sprintf("%s (%s #%d: %s)", pickle.Name, examples, pickle.Row, strings.Join(pickle.Values(), " | "))
+}
+
+func (r *JUnitReporter) failure(scenario *ScenarioResult) *junitFailure {
+	failed := scenario.FirstNotPassed()
+	f := &junitFailure{Type: failed.Status.String(), Message: failed.Error}
+	if f.Message == "" {
+		f.Message = fmt.Sprintf("%s step: %s %s", failed.Status, failed.Step.Node.StepType(), failed.Step.Text)
+	}
+	var lines []string
+	for _, step := range scenario.Steps {
+		lines = append(lines, fmt.Sprintf("%s %s ... %s", step.Step.Node.StepType(), step.Step.Text, step.Status))
+	}
+	if failed.Error != "" {
+		lines = append(lines, "", failed.Error)
+	}
+	f.Body = strings.Join(lines, "\n")
+	return f
+}
+
+func fmtSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
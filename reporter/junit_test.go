@@ -0,0 +1,102 @@
+package reporter_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/reporter"
+)
+
+const calculatorFeature = `
+Feature: Dead Simple Calculator
+
+  Background:
+    Given a Simple Calculator
+
+  Scenario: Adding 2 numbers
+     When I press the key "2"
+     Then the result should be 2
+
+  Scenario Outline: Simple Math
+     When I press "<left> <operator> <right>"
+     Then the result should be "<result>"
+
+    Examples: Additions
+     | left | operator | right | result |
+     | 2    | +        | 2     | 4      |
+     | 3    | +        | 4     | 7      |
+
+  Scenario: Dividing by zero
+     When I press "1 / 0"
+     Then an error is shown
+`
+
+// runCalculator fakes the results of running the calculator feature.
+func runCalculator() *reporter.FeatureResult {
+	feature, _ := gherkin.ParseGherkinFeature(calculatorFeature)
+	result := reporter.NewFeatureResult("features/calculator.feature", feature)
+	for i, scenario := range result.Scenarios {
+		for _, step := range scenario.Steps {
+			step.Status = reporter.StatusPassed
+			step.Duration = 250 * time.Millisecond
+		}
+		switch i {
+		case 2:
+			scenario.Steps[2].Status = reporter.StatusFailed
+			scenario.Steps[2].Error = `expected "7" but got "8"`
+			scenario.Output = "pressed 3 + 4\n"
+		case 3:
+			scenario.Steps[1].Status = reporter.StatusPending
+			scenario.Steps[2].Status = reporter.StatusSkipped
+			scenario.Steps[2].Duration = 0
+		}
+	}
+	return result
+}
+
+func ExampleJUnitReporter() {
+	r := &reporter.JUnitReporter{Name: "calculator"}
+	r.Report([]*reporter.FeatureResult{runCalculator()}, os.Stdout)
+
+	// Output:
+	// <?xml version="1.0" encoding="UTF-8"?>
+	// <testsuites name="calculator" tests="4" failures="1" skipped="1" time="2.750">
+	//   <testsuite name="Dead Simple Calculator" tests="4" failures="1" skipped="1" time="2.750">
+	//     <testcase classname="Dead Simple Calculator" name="Adding 2 numbers" time="0.750"></testcase>
+	//     <testcase classname="Dead Simple Calculator" name="Simple Math (Additions #1: 2 | + | 2 | 4)" time="0.750"></testcase>
+	//     <testcase classname="Dead Simple Calculator" name="Simple Math (Additions #2: 3 | + | 4 | 7)" time="0.750">
+	//       <failure message="expected &#34;7&#34; but got &#34;8&#34;" type="failed"><![CDATA[Given a Simple Calculator ... passed
+	// When I press "3 + 4" ... passed
+	// Then the result should be "7" ... failed
+	//
+	// expected "7" but got "8"]]></failure>
+	//       <system-out><![CDATA[pressed 3 + 4
+	// ]]></system-out>
+	//     </testcase>
+	//     <testcase classname="Dead Simple Calculator" name="Dividing by zero" time="0.500">
+	//       <skipped message="pending"></skipped>
+	//     </testcase>
+	//   </testsuite>
+	// </testsuites>
+}
+
+func ExampleJUnitReporter_strict() {
+	result := runCalculator()
+	result.Scenarios = result.Scenarios[3:]
+
+	r := &reporter.JUnitReporter{Strict: true}
+	r.Report([]*reporter.FeatureResult{result}, os.Stdout)
+
+	// Output:
+	// <?xml version="1.0" encoding="UTF-8"?>
+	// <testsuites tests="1" failures="1" skipped="0" time="0.500">
+	//   <testsuite name="Dead Simple Calculator" tests="1" failures="1" skipped="0" time="0.500">
+	//     <testcase classname="Dead Simple Calculator" name="Dividing by zero" time="0.500">
+	//       <failure message="pending step: When I press &#34;1 / 0&#34;" type="pending"><![CDATA[Given a Simple Calculator ... passed
+	// When I press "1 / 0" ... pending
+	// Then an error is shown ... skipped]]></failure>
+	//     </testcase>
+	//   </testsuite>
+	// </testsuites>
+}
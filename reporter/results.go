@@ -0,0 +1,128 @@
+// Sub-Package gherkin/reporter provides the data-structure types for the
+// results of running features and writes them in various report formats.
+package reporter
+
+import (
+	"time"
+
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/pickles"
+)
+
+type Status int
+
+const (
+	StatusSkipped Status = iota
+	StatusPassed
+	StatusFailed
+	StatusPending
+	StatusUndefined
+	StatusAmbiguous
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusSkipped:
+		return "skipped"
+	case StatusPassed:
+		return "passed"
+	case StatusFailed:
+		return "failed"
+	case StatusPending:
+		return "pending"
+	case StatusUndefined:
+		return "undefined"
+	case StatusAmbiguous:
+		return "ambiguous"
+	}
+	return "unknown"
+}
+
+// FeatureResult holds the results of all pickles of a feature.
+//
+//	result := reporter.NewFeatureResult("features/calculator.feature", feature)
+//	for _, scenario := range result.Scenarios {
+//		for _, step := range scenario.Steps {
+//			start := time.Now()
+//			err := run(step.Step)
+//			step.Duration = time.Since(start)
+//			...
+//		}
+//	}
+type FeatureResult struct {
+	Path      string
+	Feature   nodes.FeatureNode
+	Scenarios []*ScenarioResult
+}
+
+type ScenarioResult struct {
+	Pickle *pickles.Pickle
+	Steps  []*StepResult
+	Output string // captured output
+}
+
+type StepResult struct {
+	Step       *pickles.Step
+	Status     Status
+	Duration   time.Duration
+	Error      string
+	Embeddings []*Embedding // e.g. screenshots
+}
+
+// Embedding is data attached to a step result.
+type Embedding struct {
+	MimeType string
+	Data     []byte
+}
+
+func (s *StepResult) Embed(mimeType string, data []byte) {
+	s.Embeddings = append(s.Embeddings, &Embedding{MimeType: mimeType, Data: data})
+}
+
+// NewFeatureResult returns the results for all pickles of feature, with every
+// step being skipped.
+func NewFeatureResult(path string, feature nodes.FeatureNode) *FeatureResult {
+	f := &FeatureResult{Path: path, Feature: feature}
+	for _, pickle := range pickles.Compile(feature) {
+		s := &ScenarioResult{Pickle: pickle}
+		for _, step := range pickle.Steps {
+			s.Steps = append(s.Steps, &StepResult{Step: step})
+		}
+		f.Scenarios = append(f.Scenarios, s)
+	}
+	return f
+}
+
+func (f *FeatureResult) Duration() time.Duration {
+	var d time.Duration
+	for _, s := range f.Scenarios {
+		d += s.Duration()
+	}
+	return d
+}
+
+// Status is the status of the first step that did not pass, or passed if all
+// steps passed.
+func (s *ScenarioResult) Status() Status {
+	if step := s.FirstNotPassed(); step != nil {
+		return step.Status
+	}
+	return StatusPassed
+}
+
+func (s *ScenarioResult) FirstNotPassed() *StepResult {
+	for _, step := range s.Steps {
+		if step.Status != StatusPassed {
+			return step
+		}
+	}
+	return nil
+}
+
+func (s *ScenarioResult) Duration() time.Duration {
+	var d time.Duration
+	for _, step := range s.Steps {
+		d += step.Duration
+	}
+	return d
+}
@@ -0,0 +1,355 @@
+// Sub-Package gherkin/snippets generates step definition stubs for undefined steps.
+package snippets
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+// Generator turns step texts into step definition snippets.
+//
+//	When I press the key "2" 3 times
+//
+// becomes
+//
+//	Step(`I press the key {string} {int} times`, iPressTheKeyTimes)
+//
+//	func iPressTheKeyTimes(arg1 string, arg2 int) error {
+//		return ErrPending
+//	}
+type Generator struct {
+	UseRegexp bool   // suggest a regular expression instead of a Cucumber expression
+	StepFunc  string // registration call, defaults to "Step"
+	Pending   string // stub return value, defaults to "ErrPending"
+}
+
+const (
+	StepFuncDefault = "Step"
+	PendingDefault  = "ErrPending"
+)
+
+type Param struct {
+	Name string
+	Type string
+}
+
+type Snippet struct {
+	Expression string
+	FuncName   string
+	Params     []Param
+
+	gen *Generator
+}
+
+// String renders the snippet as ready-to-paste Go code.
+func (s *Snippet) String() string {
+	buf := new(bytes.Buffer)
+	stepFunc, pending := StepFuncDefault, PendingDefault
+	if s.gen != nil && s.gen.StepFunc != "" {
+		stepFunc = s.gen.StepFunc
+	}
+	if s.gen != nil && s.gen.Pending != "" {
+		pending = s.gen.Pending
+	}
+	params := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		params[i] = p.Name + " " + p.Type
+	}
+	fmt.Fprintf(buf, "%s(%s, %s)\n", stepFunc, goQuote(s.Expression), s.FuncName)
+	fmt.Fprintf(buf, "\n")
+	fmt.Fprintf(buf, "func %s(%s) error {\n", s.FuncName, strings.Join(params, ", "))
+	fmt.Fprintf(buf, "\treturn %s\n", pending)
+	fmt.Fprintf(buf, "}\n")
+	return buf.String()
+}
+
+func goQuote(str string) string {
+	if !strings.Contains(str, "`") {
+		return "`" + str + "`"
+	}
+	return fmt.Sprintf("%q", str)
+}
+
+type paramKind int
+
+const (
+	literalPart paramKind = iota
+	stringParam
+	intParam
+	floatParam
+	placeholderParam
+)
+
+type textPart struct {
+	kind paramKind
+	text string
+}
+
+var paramRe = regexp.MustCompile(`"[^"]*"|'[^']*'|<[^<>\s]+>|-?\d*\.\d+|-?\d+`)
+
+// splitText splits a step text into literal parts and parameters.
+func splitText(text string) []textPart {
+	var parts []textPart
+	last := 0
+	for _, loc := range paramRe.FindAllStringIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		match := text[start:end]
+		var kind paramKind
+		switch match[0] {
+		case '"', '\'':
+			kind = stringParam
+		case '<':
+			kind = placeholderParam
+		default:
+			// numbers only count when they stand on their own
+			before, _ := utf8.DecodeLastRuneInString(text[:start])
+			after, _ := utf8.DecodeRuneInString(text[end:])
+			if !isBoundary(before) || !isBoundary(after) {
+				continue
+			}
+			if strings.Contains(match, ".") {
+				kind = floatParam
+			} else {
+				kind = intParam
+			}
+		}
+		if last < start {
+			parts = append(parts, textPart{literalPart, text[last:start]})
+		}
+		parts = append(parts, textPart{kind, match})
+		last = end
+	}
+	if last < len(text) {
+		parts = append(parts, textPart{literalPart, text[last:]})
+	}
+	return parts
+}
+
+// isBoundary reports whether r does not belong to a word, which is the case
+// for utf8.RuneError at the start and the end of the text.
+func isBoundary(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+var cucumberEscaper = strings.NewReplacer(`\`, `\\`, `(`, `\(`, `{`, `\{`, `/`, `\/`)
+
+// Snippet returns the snippet for a single step.
+func (g *Generator) Snippet(step nodes.StepNode) *Snippet {
+	s := &Snippet{gen: g}
+	expr := new(bytes.Buffer)
+	var words []string
+	argNo := 0
+	nextArg := func() string {
+		argNo++
+		return fmt.Sprintf("arg%d", argNo)
+	}
+	for _, part := range splitText(step.Text()) {
+		switch part.kind {
+		case literalPart:
+			if g.UseRegexp {
+				expr.WriteString(regexp.QuoteMeta(part.text))
+			} else {
+				expr.WriteString(cucumberEscaper.Replace(part.text))
+			}
+			words = append(words, strings.FieldsFunc(part.text, isNotAlnum)...)
+		case stringParam:
+			quote := part.text[:1]
+			if g.UseRegexp {
+				expr.WriteString(quote + "([^" + quote + "]*)" + quote)
+			} else {
+				expr.WriteString("{string}")
+			}
+			s.Params = append(s.Params, Param{nextArg(), "string"})
+		case placeholderParam:
+			if g.UseRegexp {
+				expr.WriteString(`(.*)`)
+			} else {
+				expr.WriteString("{}")
+			}
+			name := lowerCamel(strings.FieldsFunc(part.text, isNotAlnum))
+			if !isIdent(name) {
+				name = nextArg()
+			} else {
+				argNo++
+			}
+			s.Params = append(s.Params, Param{name, "string"})
+		case intParam:
+			if g.UseRegexp {
+				expr.WriteString(`(-?\d+)`)
+			} else {
+				expr.WriteString("{int}")
+			}
+			s.Params = append(s.Params, Param{nextArg(), "int"})
+		case floatParam:
+			if g.UseRegexp {
+				expr.WriteString(`(-?\d*\.\d+)`)
+			} else {
+				expr.WriteString("{float}")
+			}
+			s.Params = append(s.Params, Param{nextArg(), "float64"})
+		}
+	}
+	if step.Table() != nil {
+		s.Params = append(s.Params, Param{"table", "nodes.TableNode"})
+	} else if step.PyString() != nil {
+		s.Params = append(s.Params, Param{"docString", "nodes.PyStringNode"})
+	}
+	uniqueNames(s.Params)
+	if g.UseRegexp {
+		s.Expression = "^" + expr.String() + "$"
+	} else {
+		s.Expression = expr.String()
+	}
+	s.FuncName = lowerCamel(words)
+	if !isIdent(s.FuncName) {
+		s.FuncName = "step" + upperFirst(s.FuncName)
+	}
+	return s
+}
+
+func isNotAlnum(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// isIdent reports whether name is usable as Go identifier. The names are made
+// of letters and digits only.
+func isIdent(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsLetter(r) && !token.Lookup(name).IsKeyword()
+}
+
+// uniqueNames appends a number to the names of params used before, as in
+// "<a> plus <a>".
+func uniqueNames(params []Param) {
+	seen := make(map[string]bool)
+	for i := range params {
+		name := params[i].Name
+		for n := 2; seen[name]; n++ {
+			name = fmt.Sprintf("%s%d", params[i].Name, n)
+		}
+		params[i].Name = name
+		seen[name] = true
+	}
+}
+
+func lowerCamel(words []string) string {
+	name := ""
+	for i, word := range words {
+		if i == 0 {
+			r, size := utf8.DecodeRuneInString(word)
+			name += string(unicode.ToLower(r)) + word[size:]
+		} else {
+			name += upperFirst(word)
+		}
+	}
+	return name
+}
+
+func upperFirst(word string) string {
+	for i, r := range word {
+		return string(unicode.ToUpper(r)) + word[i+len(string(r)):]
+	}
+	return word
+}
+
+// ----------------------------------------
+
+// Snippets collects the snippets for many steps, possibly spread across many
+// features, and drops those that are identical.
+type Snippets interface {
+	AddStep(step nodes.StepNode) *Snippet
+	AddFeature(feature nodes.FeatureNode, undefined func(nodes.StepNode) bool)
+	Snippets() []*Snippet
+	WriteTo(out io.Writer) (int64, error)
+}
+
+func NewSnippets(g *Generator) Snippets {
+	if g == nil {
+		g = &Generator{}
+	}
+	return &snippets{
+		gen:         g,
+		expressions: make(map[string]*Snippet),
+		funcNames:   make(map[string]bool),
+	}
+}
+
+type snippets struct {
+	gen         *Generator
+	list        []*Snippet
+	expressions map[string]*Snippet
+	funcNames   map[string]bool
+}
+
+// AddStep adds the snippet for step unless an identical one was added before.
+// In both cases the collected snippet is returned.
+func (c *snippets) AddStep(step nodes.StepNode) *Snippet {
+	s := c.gen.Snippet(step)
+	key := s.Expression + "\x00" + fmtParams(s.Params)
+	if prev, ok := c.expressions[key]; ok {
+		return prev
+	}
+	name := s.FuncName
+	for i := 2; c.funcNames[name]; i++ {
+		name = fmt.Sprintf("%s%d", s.FuncName, i)
+	}
+	s.FuncName = name
+	c.funcNames[name] = true
+	c.expressions[key] = s
+	c.list = append(c.list, s)
+	return s
+}
+
+func fmtParams(params []Param) string {
+	str := ""
+	for _, p := range params {
+		str += p.Type + ","
+	}
+	return str
+}
+
+// AddFeature adds the snippets of all steps of feature for which undefined
+// returns true. A nil undefined func adds all steps.
+func (c *snippets) AddFeature(feature nodes.FeatureNode, undefined func(nodes.StepNode) bool) {
+	var scenarios []nodes.ScenarioNode
+	if feature.Background() != nil {
+		scenarios = append(scenarios, feature.Background())
+	}
+	scenarios = append(scenarios, feature.Scenarios()...)
+	for _, scenario := range scenarios {
+		for _, step := range scenario.Steps() {
+			if undefined == nil || undefined(step) {
+				c.AddStep(step)
+			}
+		}
+	}
+}
+
+func (c *snippets) Snippets() []*Snippet {
+	return c.list
+}
+
+// WriteTo writes all collected snippets separated by blank lines.
+func (c *snippets) WriteTo(out io.Writer) (int64, error) {
+	var total int64
+	for i, s := range c.list {
+		str := s.String()
+		if i > 0 {
+			str = "\n" + str
+		}
+		n, err := io.WriteString(out, str)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
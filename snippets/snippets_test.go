@@ -0,0 +1,169 @@
+package snippets_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/snippets"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleSnippets() {
+	feature, _ := gherkin.ParseGherkinFeature(`
+Feature: Dead Simple Calculator
+
+  Scenario: Adding 2 numbers
+     When I press the key "2"
+      And I press the key "+"
+      And I press the key "2" 3 times
+     Then the result should be 4.5
+
+  Scenario: Follow user actions
+     When I do the following user actions:
+       | action   | key |
+       | key down | 2   |
+     Then the result should be 6
+`)
+
+	s := snippets.NewSnippets(nil)
+	s.AddFeature(feature, nil)
+	s.WriteTo(os.Stdout)
+
+	// Output:
+	// Step(`I press the key {string}`, iPressTheKey)
+	//
+	// func iPressTheKey(arg1 string) error {
+	// 	return ErrPending
+	// }
+	//
+	// Step(`I press the key {string} {int} times`, iPressTheKeyTimes)
+	//
+	// func iPressTheKeyTimes(arg1 string, arg2 int) error {
+	// 	return ErrPending
+	// }
+	//
+	// Step(`the result should be {float}`, theResultShouldBe)
+	//
+	// func theResultShouldBe(arg1 float64) error {
+	// 	return ErrPending
+	// }
+	//
+	// Step(`I do the following user actions:`, iDoTheFollowingUserActions)
+	//
+	// func iDoTheFollowingUserActions(table nodes.TableNode) error {
+	// 	return ErrPending
+	// }
+	//
+	// Step(`the result should be {int}`, theResultShouldBe2)
+	//
+	// func theResultShouldBe2(arg1 int) error {
+	// 	return ErrPending
+	// }
+}
+
+func TestSnippetRegexp(t *testing.T) {
+	gen := &snippets.Generator{UseRegexp: true, StepFunc: "s.Step", Pending: "godog.ErrPending"}
+
+	step := nodes.NewMutableStepNode("Given", `a user 'bob' with (-12) coins`)
+	s := gen.Snippet(step)
+	assert.Equal(t, `^a user '([^']*)' with \((-?\d+)\) coins$`, s.Expression)
+	assert.Equal(t, "aUserWithCoins", s.FuncName)
+	assert.Equal(t, "s.Step(`^a user '([^']*)' with \\((-?\\d+)\\) coins$`, aUserWithCoins)\n\n"+
+		"func aUserWithCoins(arg1 string, arg2 int) error {\n\treturn godog.ErrPending\n}\n", s.String())
+}
+
+func TestSnippetCucumberEscaping(t *testing.T) {
+	gen := &snippets.Generator{}
+
+	s := gen.Snippet(nodes.NewMutableStepNode("Then", `the path a/b (or {c}) is shown`))
+	assert.Equal(t, `the path a\/b \(or \{c}) is shown`, s.Expression)
+	assert.Equal(t, "thePathABOrCIsShown", s.FuncName)
+	assert.Empty(t, s.Params)
+}
+
+func TestSnippetNumbersInsideWords(t *testing.T) {
+	gen := &snippets.Generator{}
+
+	s := gen.Snippet(nodes.NewMutableStepNode("Given", `an mp3 file of 128 kbit`))
+	assert.Equal(t, `an mp3 file of {int} kbit`, s.Expression)
+	assert.Equal(t, []snippets.Param{{"arg1", "int"}}, s.Params)
+
+	s = gen.Snippet(nodes.NewMutableStepNode("Given", `die Straße3 und 4ü sind 5 €`))
+	assert.Equal(t, `die Straße3 und 4ü sind {int} €`, s.Expression)
+	assert.Equal(t, []snippets.Param{{"arg1", "int"}}, s.Params)
+}
+
+func TestSnippetOutlinePlaceholders(t *testing.T) {
+	gen := &snippets.Generator{}
+
+	s := gen.Snippet(nodes.NewMutableStepNode("When", `I add <left> and <right hand side>`))
+	assert.Equal(t, `I add {} and <right hand side>`, s.Expression)
+
+	s = gen.Snippet(nodes.NewMutableStepNode("When", `I add <left> and <right_side>`))
+	assert.Equal(t, `I add {} and {}`, s.Expression)
+	assert.Equal(t, []snippets.Param{{"left", "string"}, {"rightSide", "string"}}, s.Params)
+
+	s = gen.Snippet(nodes.NewMutableStepNode("When", `I add <a> and <a> of <type> to <arg1> as "x"`))
+	assert.Equal(t, []snippets.Param{
+		{"a", "string"},
+		{"a2", "string"},
+		{"arg3", "string"},
+		{"arg1", "string"},
+		{"arg5", "string"},
+	}, s.Params)
+
+	s = gen.Snippet(nodes.NewMutableStepNode("When", `I add <arg2> to "x"`))
+	assert.Equal(t, []snippets.Param{{"arg2", "string"}, {"arg22", "string"}}, s.Params)
+}
+
+func TestSnippetFuncNames(t *testing.T) {
+	gen := &snippets.Generator{}
+
+	s := gen.Snippet(nodes.NewMutableStepNode("Given", `Übersicht der Konten`))
+	assert.Equal(t, "übersichtDerKonten", s.FuncName)
+
+	s = gen.Snippet(nodes.NewMutableStepNode("When", `go`))
+	assert.Equal(t, "stepGo", s.FuncName)
+}
+
+func TestSnippetDocString(t *testing.T) {
+	gen := &snippets.Generator{}
+
+	step := nodes.NewMutableStepNode("When", `I press the following keys:`).
+		WithPyString(nodes.NewMutablePyStringNode().WithLines([]string{"2", "+ 2"}))
+	s := gen.Snippet(step)
+	assert.Equal(t, []snippets.Param{{"docString", "nodes.PyStringNode"}}, s.Params)
+}
+
+func TestSnippetsDeduplicateAcrossFeatures(t *testing.T) {
+	f1, err := gherkin.ParseGherkinFeature(`
+Feature: One
+  Background:
+    Given a Simple Calculator
+  Scenario: A
+    When I press the key "2"
+`)
+	assert.NoError(t, err)
+	f2, err := gherkin.ParseGherkinFeature(`
+Feature: Two
+  Scenario: B
+    Given a Simple Calculator
+    When I press the key "7"
+    Then the display is empty
+`)
+	assert.NoError(t, err)
+
+	s := snippets.NewSnippets(nil)
+	s.AddFeature(f1, nil)
+	s.AddFeature(f2, func(step nodes.StepNode) bool {
+		return step.Text() != "the display is empty"
+	})
+
+	var exprs []string
+	for _, snippet := range s.Snippets() {
+		exprs = append(exprs, snippet.Expression)
+	}
+	assert.Equal(t, []string{"a Simple Calculator", "I press the key {string}"}, exprs)
+}
@@ -0,0 +1,149 @@
+package steps
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultStepFuncs are the names of the functions and methods the Analyzer
+// takes for step registrations, like Registry.Step or godog's ctx.Step.
+var DefaultStepFuncs = []string{"Step", "Define", "Given", "When", "Then"}
+
+// Analyzer finds step definitions in Go source code without compiling it.
+// Every call of one of the step funcs whose first argument is a string
+// literal counts as registration:
+//
+//	ctx.Step(`^I press the key "([^"]*)"$`, iPressTheKey)
+//	r.Step(`the result should be {int}`, theResultShouldBe)
+//
+// The Func of the definitions found is the source of the second argument,
+// if any, like "iPressTheKey".
+type Analyzer interface {
+	// ParseFile adds the definitions registered in a Go source file. If src
+	// is nil, the file is read from filename.
+	ParseFile(filename string, src interface{}) error
+	// ParseDir adds the definitions of all .go files below dir, skipping
+	// hidden, vendor and testdata directories. It keeps going on errors and
+	// returns the first one.
+	ParseDir(dir string) error
+	Definitions() []*Definition
+}
+
+// NewAnalyzer returns an analyzer for registrations by the given funcs, or by
+// DefaultStepFuncs if there are none.
+func NewAnalyzer(funcs ...string) Analyzer {
+	if len(funcs) == 0 {
+		funcs = DefaultStepFuncs
+	}
+	a := &analyzer{fset: token.NewFileSet(), funcs: make(map[string]bool)}
+	for _, name := range funcs {
+		a.funcs[name] = true
+	}
+	return a
+}
+
+type analyzer struct {
+	fset        *token.FileSet
+	funcs       map[string]bool
+	definitions []*Definition
+}
+
+// ExpressionError is returned for registrations whose expression does not
+// compile.
+type ExpressionError struct {
+	File       string
+	Line       int
+	Column     int
+	Expression string
+	Err        error
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Err)
+}
+
+func (a *analyzer) ParseFile(filename string, src interface{}) error {
+	file, err := parser.ParseFile(a.fset, filename, src, 0)
+	if err != nil {
+		return err
+	}
+	var first error
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 || !a.funcs[funcName(call.Fun)] {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		expr, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		pos := a.fset.Position(call.Pos())
+		re, err := Compile(expr)
+		if err != nil {
+			if first == nil {
+				first = &ExpressionError{pos.Filename, pos.Line, pos.Column, expr, err}
+			}
+			return true
+		}
+		d := &Definition{Expression: expr, Regexp: re, File: pos.Filename, Line: pos.Line, Column: pos.Column}
+		if len(call.Args) > 1 {
+			d.Func = types.ExprString(call.Args[1])
+		}
+		a.definitions = append(a.definitions, d)
+		return true
+	})
+	return first
+}
+
+// funcName returns the name of the function or method called, "" if it is
+// not called by name.
+func funcName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+	return ""
+}
+
+func (a *analyzer) ParseDir(dir string) error {
+	var first error
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") {
+			if err := a.ParseFile(path, nil); err != nil && first == nil {
+				first = err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return first
+}
+
+func (a *analyzer) Definitions() []*Definition {
+	return a.definitions
+}
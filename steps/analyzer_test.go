@@ -0,0 +1,77 @@
+package steps_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/steps"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzer(t *testing.T) {
+	a := steps.NewAnalyzer()
+	assert.NoError(t, a.ParseDir("testdata/calculator"))
+
+	var found []string
+	for _, d := range a.Definitions() {
+		found = append(found, fmt.Sprintf("%s:%d:%d: %s %v", filepath.ToSlash(d.File), d.Line, d.Column, d.Expression, d.Func))
+	}
+	assert.Equal(t, []string{
+		"testdata/calculator/calculator_test.go:7:2: ^a Simple Calculator$ c.reset",
+		"testdata/calculator/calculator_test.go:8:2: I press the key {string} c.press",
+		"testdata/calculator/calculator_test.go:9:2: the result should be {int} (func(n int) error literal)",
+		"testdata/calculator/internal/display.go:4:2: the display is blank displayIsBlank",
+		"testdata/calculator/internal/display.go:5:2: the display shows {word} displayShows",
+	}, found)
+}
+
+func TestAnalyzerErrors(t *testing.T) {
+	a := steps.NewAnalyzer("Given")
+	err := a.ParseFile("steps.go", "package p\n\nfunc init() {\n\tGiven(`I have {color} eyes`, nil)\n\tGiven(`a cucumber`, nil)\n\tStep(`ignored`, nil)\n}\n")
+	assert.EqualError(t, err, `steps.go:4:2: undefined parameter type {color} in expression "I have {color} eyes"`)
+	if assert.Len(t, a.Definitions(), 1) {
+		assert.Equal(t, "a cucumber", a.Definitions()[0].Expression)
+	}
+
+	assert.Error(t, a.ParseFile("broken.go", "package p\nfunc {"))
+}
+
+func TestIndex(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature(calculatorFeature)
+	assert.NoError(t, err)
+
+	a := steps.NewAnalyzer()
+	assert.NoError(t, a.ParseDir("testdata/calculator"))
+	index := steps.NewIndex(a.Definitions())
+	index.AddFeature("calculator.feature", feature)
+
+	background := feature.Background().Steps()[0]
+	definitions := index.Definitions(background)
+	if assert.Len(t, definitions, 1) {
+		assert.Equal(t, "^a Simple Calculator$", definitions[0].Expression)
+		assert.Equal(t, 7, definitions[0].Line)
+
+		refs := index.References(definitions[0])
+		if assert.Len(t, refs, 1) {
+			assert.Equal(t, "calculator.feature", refs[0].File)
+			assert.Equal(t, background, refs[0].Step)
+		}
+	}
+
+	press := a.Definitions()[1]
+	var lines []int
+	for _, ref := range index.References(press) {
+		lines = append(lines, ref.Step.Position().Line)
+	}
+	assert.Equal(t, []int{8, 9, 10, 11, 15, 15, 16, 16, 17, 17, 18}, lines)
+	assert.Empty(t, index.References(a.Definitions()[3]))
+
+	var undefined []string
+	for _, ref := range index.Undefined() {
+		undefined = append(undefined, fmt.Sprintf("%d: %s", ref.Step.Position().Line, ref.Text))
+	}
+	assert.Equal(t, []string{`19: the result should be "4"`, `19: the result should be "3"`}, undefined)
+	assert.Empty(t, index.Definitions(feature.Scenarios()[1].Steps()[4]))
+}
@@ -0,0 +1,199 @@
+package steps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/muhqu/go-gherkin/nodes"
+	"github.com/muhqu/go-gherkin/pickles"
+)
+
+// DryRun matches the steps of parsed features against the definitions of a
+// registry without running anything.
+//
+//	dr := steps.NewDryRun(registry)
+//	dr.AddFeature("features/calculator.feature", feature)
+//	report := dr.Report()
+//	report.WriteJSON(os.Stdout)
+//	if !report.OK() {
+//		os.Exit(1)
+//	}
+type DryRun interface {
+	AddFeature(path string, feature nodes.FeatureNode)
+	Report() *Report
+}
+
+func NewDryRun(registry Registry) DryRun {
+	return &dryRun{
+		registry: registry,
+		used:     make(map[*Definition]bool),
+	}
+}
+
+type dryRun struct {
+	registry  Registry
+	used      map[*Definition]bool
+	undefined []*StepIssue
+	ambiguous []*StepIssue
+}
+
+func (d *dryRun) AddFeature(path string, feature nodes.FeatureNode) {
+	var scenarios []nodes.ScenarioNode
+	if feature.Background() != nil {
+		scenarios = append(scenarios, feature.Background())
+	}
+	scenarios = append(scenarios, feature.Scenarios()...)
+	for _, scenario := range scenarios {
+		for _, step := range scenario.Steps() {
+			for _, text := range stepTexts(scenario, step) {
+				matches := d.registry.Match(text)
+				for _, m := range matches {
+					d.used[m] = true
+				}
+				if len(matches) == 1 {
+					continue
+				}
+				issue := newStepIssue(path, step, text)
+				if len(matches) == 0 {
+					d.undefined = append(d.undefined, issue)
+				} else {
+					for _, m := range matches {
+						issue.Matches = append(issue.Matches, newDefinitionRef(m))
+					}
+					d.ambiguous = append(d.ambiguous, issue)
+				}
+			}
+		}
+	}
+}
+
+// stepTexts returns the texts step is run with. For steps of a scenario
+// outline, that is the step text expanded with each row of the examples.
+func stepTexts(scenario nodes.ScenarioNode, step nodes.StepNode) []string {
+	outline, ok := scenario.(nodes.OutlineNode)
+	if !ok || len(outline.AllExamples()) == 0 {
+		return []string{step.Text()}
+	}
+	var texts []string
+	seen := make(map[string]bool)
+	for _, examples := range outline.AllExamples() {
+		if examples.Table() == nil || len(examples.Table().Rows()) == 0 {
+			continue
+		}
+		rows := examples.Table().Rows()
+		header := rows[0]
+		for _, row := range rows[1:] {
+			text := pickles.Substitute(step.Text(), header, row)
+			if !seen[text] {
+				seen[text] = true
+				texts = append(texts, text)
+			}
+		}
+	}
+	if len(texts) == 0 {
+		return []string{step.Text()}
+	}
+	return texts
+}
+
+func (d *dryRun) Report() *Report {
+	r := &Report{
+		Undefined: append([]*StepIssue{}, d.undefined...),
+		Ambiguous: append([]*StepIssue{}, d.ambiguous...),
+		Unused:    []*DefinitionRef{},
+	}
+	for _, def := range d.registry.Definitions() {
+		if !d.used[def] {
+			r.Unused = append(r.Unused, newDefinitionRef(def))
+		}
+	}
+	return r
+}
+
+// ----------------------------------------
+
+// Report of a dry-run, ready to be written as JSON.
+type Report struct {
+	Undefined []*StepIssue     `json:"undefined"`
+	Ambiguous []*StepIssue     `json:"ambiguous"`
+	Unused    []*DefinitionRef `json:"unused"`
+}
+
+type StepIssue struct {
+	File    string           `json:"file"`
+	Line    int              `json:"line"`
+	Column  int              `json:"column"`
+	Keyword string           `json:"keyword"`
+	Text    string           `json:"text"`
+	Matches []*DefinitionRef `json:"matches,omitempty"`
+}
+
+type DefinitionRef struct {
+	Expression string `json:"expression"`
+	File       string `json:"file,omitempty"`
+	Line       int    `json:"line,omitempty"`
+}
+
+func newStepIssue(path string, step nodes.StepNode, text string) *StepIssue {
+	pos := step.Position()
+	return &StepIssue{
+		File:    path,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Keyword: step.StepType(),
+		Text:    text,
+	}
+}
+
+func newDefinitionRef(d *Definition) *DefinitionRef {
+	return &DefinitionRef{Expression: d.Expression, File: d.File, Line: d.Line}
+}
+
+func (d *DefinitionRef) String() string {
+	if d.File == "" {
+		return d.Expression
+	}
+	return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Expression)
+}
+
+// OK reports whether every step matched exactly one definition. Unused
+// definitions do not count as failure.
+func (r *Report) OK() bool {
+	return len(r.Undefined) == 0 && len(r.Ambiguous) == 0
+}
+
+func (r *Report) WriteJSON(out io.Writer) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(b, '\n'))
+	return err
+}
+
+// WriteText writes one line per issue, in the file:line:column format most
+// editors and CI systems pick up.
+func (r *Report) WriteText(out io.Writer) error {
+	for _, issue := range r.Undefined {
+		if _, err := fmt.Fprintf(out, "%s:%d:%d: undefined step: %s %s\n", issue.File, issue.Line, issue.Column, issue.Keyword, issue.Text); err != nil {
+			return err
+		}
+	}
+	for _, issue := range r.Ambiguous {
+		if _, err := fmt.Fprintf(out, "%s:%d:%d: ambiguous step: %s %s\n", issue.File, issue.Line, issue.Column, issue.Keyword, issue.Text); err != nil {
+			return err
+		}
+		for _, m := range issue.Matches {
+			if _, err := fmt.Fprintf(out, "\tmatches %s\n", m); err != nil {
+				return err
+			}
+		}
+	}
+	for _, def := range r.Unused {
+		if _, err := fmt.Fprintf(out, "%s: unused step definition\n", def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
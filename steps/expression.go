@@ -0,0 +1,217 @@
+package steps
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParameterTypes maps the names of the built-in Cucumber expression
+// parameter types to the regular expressions matching them.
+var ParameterTypes = map[string]string{
+	"":           `(.*)`,
+	"int":        `(-?\d+)`,
+	"byte":       `(-?\d+)`,
+	"short":      `(-?\d+)`,
+	"long":       `(-?\d+)`,
+	"biginteger": `(-?\d+)`,
+	"float":      `(-?\d*\.?\d+(?:[eE][-+]?\d+)?)`,
+	"double":     `(-?\d*\.?\d+(?:[eE][-+]?\d+)?)`,
+	"bigdecimal": `(-?\d*\.?\d+)`,
+	"word":       `([^\s]+)`,
+	"string":     `("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')`,
+}
+
+// IsRegexp reports whether expr is meant as regular expression rather than
+// as Cucumber expression, which is the case when it is anchored with ^ or $
+// or enclosed in slashes.
+func IsRegexp(expr string) bool {
+	return strings.HasPrefix(expr, "^") || strings.HasSuffix(expr, "$") ||
+		(len(expr) > 1 && strings.HasPrefix(expr, "/") && strings.HasSuffix(expr, "/"))
+}
+
+// Compile compiles a step definition expression, which is either a regular
+// expression or a Cucumber expression.
+func Compile(expr string) (*regexp.Regexp, error) {
+	if IsRegexp(expr) {
+		if len(expr) > 1 && strings.HasPrefix(expr, "/") && strings.HasSuffix(expr, "/") {
+			expr = expr[1 : len(expr)-1]
+		}
+		return regexp.Compile(expr)
+	}
+	re, err := CucumberExpressionToRegexp(expr)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(re)
+}
+
+// CucumberExpressionToRegexp translates a Cucumber expression like
+//
+//	I have {int} cucumber(s) in my belly/stomach
+//
+// into an anchored regular expression.
+func CucumberExpressionToRegexp(expr string) (string, error) {
+	tokens, err := tokenizeCucumberExpression(expr)
+	if err != nil {
+		return "", fmt.Errorf("%s in expression %q", err, expr)
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString("^")
+	var word []cucumberToken
+	for _, t := range append(tokens, cucumberToken{kind: spaceToken}) {
+		if t.kind != spaceToken {
+			word = append(word, t)
+			continue
+		}
+		if err := writeCucumberWord(buf, word); err != nil {
+			return "", fmt.Errorf("%s in expression %q", err, expr)
+		}
+		buf.WriteString(regexp.QuoteMeta(t.text))
+		word = nil
+	}
+	buf.WriteString("$")
+	return buf.String(), nil
+}
+
+// writeCucumberWord writes the tokens between two runs of whitespace, which
+// may be alternatives separated by '/'.
+func writeCucumberWord(buf *bytes.Buffer, word []cucumberToken) error {
+	alternatives := [][]cucumberToken{nil}
+	for _, t := range word {
+		if t.kind == alternationToken {
+			alternatives = append(alternatives, nil)
+		} else {
+			alternatives[len(alternatives)-1] = append(alternatives[len(alternatives)-1], t)
+		}
+	}
+	if len(alternatives) == 1 {
+		writeCucumberTokens(buf, word)
+		return nil
+	}
+	buf.WriteString("(?:")
+	for i, alternative := range alternatives {
+		if len(alternative) == 0 {
+			return fmt.Errorf("empty alternative")
+		}
+		for _, t := range alternative {
+			if t.kind == parameterToken {
+				return fmt.Errorf("parameter inside alternation at %d", t.pos)
+			}
+		}
+		if i > 0 {
+			buf.WriteString("|")
+		}
+		writeCucumberTokens(buf, alternative)
+	}
+	buf.WriteString(")")
+	return nil
+}
+
+type cucumberTokenKind int
+
+const (
+	textToken        cucumberTokenKind = iota // literal text, unescaped
+	spaceToken                                // whitespace between words
+	parameterToken                            // {name}, text is the regexp of the parameter type
+	optionalToken                             // (text), text is unescaped
+	alternationToken                          // the '/' between alternatives
+)
+
+type cucumberToken struct {
+	kind cucumberTokenKind
+	text string
+	pos  int // byte offset within the expression
+}
+
+// tokenizeCucumberExpression splits expr into tokens. Optional text may
+// contain whitespace, but neither parameters, optionals nor alternations.
+func tokenizeCucumberExpression(expr string) ([]cucumberToken, error) {
+	var tokens []cucumberToken
+	text := func(pos int, str string) {
+		if n := len(tokens); n > 0 && tokens[n-1].kind == textToken {
+			tokens[n-1].text += str
+		} else {
+			tokens = append(tokens, cucumberToken{textToken, str, pos})
+		}
+	}
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '\\':
+			if i+1 < len(expr) {
+				i++
+			}
+			text(i, expr[i:i+1])
+		case isSpace(c):
+			start := i
+			for i+1 < len(expr) && isSpace(expr[i+1]) {
+				i++
+			}
+			tokens = append(tokens, cucumberToken{spaceToken, expr[start : i+1], start})
+		case c == '{':
+			end := strings.IndexByte(expr[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated parameter at %d", i)
+			}
+			name := expr[i+1 : i+end]
+			re, ok := ParameterTypes[name]
+			if !ok {
+				return nil, fmt.Errorf("undefined parameter type {%s}", name)
+			}
+			tokens = append(tokens, cucumberToken{parameterToken, re, i})
+			i += end
+		case c == '(':
+			optional, end, err := optionalText(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, cucumberToken{optionalToken, optional, i})
+			i = end
+		case c == '/':
+			tokens = append(tokens, cucumberToken{alternationToken, "/", i})
+		default:
+			text(i, expr[i:i+1])
+		}
+	}
+	return tokens, nil
+}
+
+// optionalText returns the unescaped text of the optional starting at the '('
+// at start, and the index of its closing ')'.
+func optionalText(expr string, start int) (string, int, error) {
+	buf := new(bytes.Buffer)
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if i+1 < len(expr) {
+				i++
+			}
+		case ')':
+			return buf.String(), i, nil
+		case '{', '(':
+			return "", 0, fmt.Errorf("parameter or optional inside optional text at %d", start)
+		case '/':
+			return "", 0, fmt.Errorf("alternation inside optional text at %d", start)
+		}
+		buf.WriteByte(expr[i])
+	}
+	return "", 0, fmt.Errorf("unterminated optional text at %d", start)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func writeCucumberTokens(buf *bytes.Buffer, tokens []cucumberToken) {
+	for _, t := range tokens {
+		switch t.kind {
+		case parameterToken:
+			buf.WriteString(t.text)
+		case optionalToken:
+			buf.WriteString("(?:" + regexp.QuoteMeta(t.text) + ")?")
+		default:
+			buf.WriteString(regexp.QuoteMeta(t.text))
+		}
+	}
+}
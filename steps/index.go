@@ -0,0 +1,99 @@
+package steps
+
+import (
+	"github.com/muhqu/go-gherkin/nodes"
+)
+
+// Index matches the steps of parsed features against step definitions, in
+// both directions: from a step to the definitions implementing it and from a
+// definition to the steps using it.
+//
+//	a := steps.NewAnalyzer()
+//	a.ParseDir(".")
+//	index := steps.NewIndex(a.Definitions())
+//	index.AddFeature("features/calculator.feature", feature)
+//	for _, def := range index.Definitions(step) {
+//		fmt.Printf("%s:%d:%d\n", def.File, def.Line, def.Column)
+//	}
+type Index interface {
+	AddFeature(path string, feature nodes.FeatureNode)
+	// Definitions returns the definitions matching step. Steps of scenario
+	// outlines match the definitions of all their expanded texts.
+	Definitions(step nodes.StepNode) []*Definition
+	// References returns the steps matching def, in the order they were
+	// added.
+	References(def *Definition) []*Reference
+	// Undefined returns the steps no definition matches.
+	Undefined() []*Reference
+}
+
+// Reference of a step to the definitions matching its text. Steps of
+// scenario outlines have one reference per expanded text.
+type Reference struct {
+	File        string
+	Step        nodes.StepNode
+	Text        string
+	Definitions []*Definition
+}
+
+func NewIndex(definitions []*Definition) Index {
+	return &index{
+		definitions: definitions,
+		steps:       make(map[nodes.StepNode][]*Reference),
+		references:  make(map[*Definition][]*Reference),
+	}
+}
+
+type index struct {
+	definitions []*Definition
+	steps       map[nodes.StepNode][]*Reference
+	references  map[*Definition][]*Reference
+	undefined   []*Reference
+}
+
+func (x *index) AddFeature(path string, feature nodes.FeatureNode) {
+	var scenarios []nodes.ScenarioNode
+	if feature.Background() != nil {
+		scenarios = append(scenarios, feature.Background())
+	}
+	scenarios = append(scenarios, feature.Scenarios()...)
+	for _, scenario := range scenarios {
+		for _, step := range scenario.Steps() {
+			for _, text := range stepTexts(scenario, step) {
+				ref := &Reference{File: path, Step: step, Text: text}
+				for _, d := range x.definitions {
+					if d.Match(text) {
+						ref.Definitions = append(ref.Definitions, d)
+						x.references[d] = append(x.references[d], ref)
+					}
+				}
+				if len(ref.Definitions) == 0 {
+					x.undefined = append(x.undefined, ref)
+				}
+				x.steps[step] = append(x.steps[step], ref)
+			}
+		}
+	}
+}
+
+func (x *index) Definitions(step nodes.StepNode) []*Definition {
+	var definitions []*Definition
+	seen := make(map[*Definition]bool)
+	for _, ref := range x.steps[step] {
+		for _, d := range ref.Definitions {
+			if !seen[d] {
+				seen[d] = true
+				definitions = append(definitions, d)
+			}
+		}
+	}
+	return definitions
+}
+
+func (x *index) References(def *Definition) []*Reference {
+	return x.references[def]
+}
+
+func (x *index) Undefined() []*Reference {
+	return x.undefined
+}
@@ -0,0 +1,92 @@
+// Sub-Package gherkin/steps provides a registry of step definitions and a
+// dry-run that checks parsed features against it, as well as an analyzer
+// finding step definitions in Go source code and an index matching them
+// against the steps of features.
+package steps
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+)
+
+// Definition of a step, matching step texts either by regular expression or
+// by Cucumber expression.
+type Definition struct {
+	Expression string
+	Regexp     *regexp.Regexp
+	Func       interface{}
+
+	// where the definition was registered, if known
+	File   string
+	Line   int
+	Column int
+}
+
+func (d *Definition) Match(text string) bool {
+	return d.Regexp.MatchString(text)
+}
+
+func (d *Definition) String() string {
+	if d.File == "" {
+		return d.Expression
+	}
+	return fmt.Sprintf("%s (%s:%d)", d.Expression, d.File, d.Line)
+}
+
+type Registry interface {
+	// Step registers a step definition and panics if expr does not compile.
+	Step(expr string, fn interface{}) *Definition
+	// Define registers a step definition.
+	Define(expr string, fn interface{}) (*Definition, error)
+	Definitions() []*Definition
+	// Match returns all definitions matching text, in registration order.
+	Match(text string) []*Definition
+}
+
+func NewRegistry() Registry {
+	return &registry{}
+}
+
+type registry struct {
+	definitions []*Definition
+}
+
+func (r *registry) Step(expr string, fn interface{}) *Definition {
+	d, err := r.define(expr, fn, 2)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (r *registry) Define(expr string, fn interface{}) (*Definition, error) {
+	return r.define(expr, fn, 2)
+}
+
+func (r *registry) define(expr string, fn interface{}, skip int) (*Definition, error) {
+	re, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	d := &Definition{Expression: expr, Regexp: re, Func: fn}
+	if _, file, line, ok := runtime.Caller(skip); ok {
+		d.File, d.Line = file, line
+	}
+	r.definitions = append(r.definitions, d)
+	return d, nil
+}
+
+func (r *registry) Definitions() []*Definition {
+	return r.definitions
+}
+
+func (r *registry) Match(text string) []*Definition {
+	var matches []*Definition
+	for _, d := range r.definitions {
+		if d.Match(text) {
+			matches = append(matches, d)
+		}
+	}
+	return matches
+}
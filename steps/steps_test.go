@@ -0,0 +1,206 @@
+package steps_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/steps"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCucumberExpressionToRegexp(t *testing.T) {
+	for expr, expected := range map[string]string{
+		`I press the key {string}`:          `^I press the key ("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*')$`,
+		`I have {int} cucumber(s)`:          `^I have (-?\d+) cucumber(?:s)?$`,
+		`in my belly/stomach/tummy`:         `^in my (?:belly|stomach|tummy)$`,
+		`the result is {float} \(rounded\)`: `^the result is (-?\d*\.?\d+(?:[eE][-+]?\d+)?) \(rounded\)$`,
+		`a {word} and {}`:                   `^a ([^\s]+) and (.*)$`,
+		`1 + 1 = 2`:                         `^1 \+ 1 = 2$`,
+		`I have (a lot of) cukes`:           `^I have (?:a lot of)? cukes$`,
+		`a (b\)) c/d(e f)`:                  `^a (?:b\))? (?:c|d(?:e f)?)$`,
+	} {
+		re, err := steps.CucumberExpressionToRegexp(expr)
+		assert.NoError(t, err, expr)
+		assert.Equal(t, expected, re, expr)
+	}
+
+	for _, expr := range []string{
+		`I have {color} eyes`,
+		`I have {int eyes`,
+		`a (b`,
+		`red/{int}`,
+		`red//blue`,
+		`a (b/c)`,
+		`a ({int})`,
+	} {
+		_, err := steps.CucumberExpressionToRegexp(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	re, err := steps.Compile(`^I press the key "([^"]*)"$`)
+	assert.NoError(t, err)
+	assert.True(t, re.MatchString(`I press the key "2"`))
+
+	re, err = steps.Compile(`/the result should be \d+/`)
+	assert.NoError(t, err)
+	assert.True(t, re.MatchString(`the result should be 4`))
+
+	re, err = steps.Compile(`I have {int} cucumber(s) in my belly/stomach`)
+	assert.NoError(t, err)
+	assert.True(t, re.MatchString(`I have 1 cucumber in my belly`))
+	assert.True(t, re.MatchString(`I have 42 cucumbers in my stomach`))
+	assert.False(t, re.MatchString(`I have many cucumbers in my belly`))
+
+	re, err = steps.Compile(`I have (a lot of )cukes`)
+	assert.NoError(t, err)
+	assert.True(t, re.MatchString(`I have a lot of cukes`))
+	assert.True(t, re.MatchString(`I have cukes`))
+}
+
+func TestRegistry(t *testing.T) {
+	r := steps.NewRegistry()
+	d := r.Step(`I press the key {string}`, nil)
+	assert.Contains(t, d.File, "steps_test.go")
+	assert.NotZero(t, d.Line)
+
+	_, err := r.Define(`I press {unknown}`, nil)
+	assert.Error(t, err)
+	assert.Panics(t, func() { r.Step(`I press {unknown}`, nil) })
+
+	assert.Len(t, r.Definitions(), 1)
+	assert.Equal(t, []*steps.Definition{d}, r.Match(`I press the key "+"`))
+	assert.Empty(t, r.Match(`I press the button "+"`))
+}
+
+const calculatorFeature = `
+Feature: Dead Simple Calculator
+
+  Background:
+    Given a Simple Calculator
+
+  Scenario: Adding 2 numbers
+     When I press the key "2"
+      And I press the key "+"
+      And I press the key "2"
+      And I press the key "="
+     Then the result should be 4
+
+  Scenario Outline: Simple Math
+     When I press the key "<left>"
+      And I press the key "<operator>"
+      And I press the key "<right>"
+      And I press the key "="
+     Then the result should be "<result>"
+
+    Examples:
+     | left | operator | right | result |
+     | 2    | +        | 2     | 4      |
+     | 9    | /        | 3     | 3      |
+`
+
+func newCalculatorRegistry() steps.Registry {
+	r := steps.NewRegistry()
+	r.Define(`a Simple Calculator`, nil)
+	r.Define(`I press the key {string}`, nil)
+	r.Define(`^I press the key "/"$`, nil)
+	r.Define(`the result should be {int}`, nil)
+	r.Define(`the display is blank`, nil)
+	for i, d := range r.Definitions() {
+		d.File, d.Line = "calculator_steps.go", 10*(i+1)
+	}
+	return r
+}
+
+func ExampleDryRun() {
+	feature, _ := gherkin.ParseGherkinFeature(calculatorFeature)
+
+	dr := steps.NewDryRun(newCalculatorRegistry())
+	dr.AddFeature("calculator.feature", feature)
+	dr.Report().WriteJSON(os.Stdout)
+
+	// Output:
+	// {
+	//   "undefined": [
+	//     {
+	//       "file": "calculator.feature",
+	//       "line": 19,
+	//       "column": 6,
+	//       "keyword": "Then",
+	//       "text": "the result should be \"4\""
+	//     },
+	//     {
+	//       "file": "calculator.feature",
+	//       "line": 19,
+	//       "column": 6,
+	//       "keyword": "Then",
+	//       "text": "the result should be \"3\""
+	//     }
+	//   ],
+	//   "ambiguous": [
+	//     {
+	//       "file": "calculator.feature",
+	//       "line": 16,
+	//       "column": 7,
+	//       "keyword": "And",
+	//       "text": "I press the key \"/\"",
+	//       "matches": [
+	//         {
+	//           "expression": "I press the key {string}",
+	//           "file": "calculator_steps.go",
+	//           "line": 20
+	//         },
+	//         {
+	//           "expression": "^I press the key \"/\"$",
+	//           "file": "calculator_steps.go",
+	//           "line": 30
+	//         }
+	//       ]
+	//     }
+	//   ],
+	//   "unused": [
+	//     {
+	//       "expression": "the display is blank",
+	//       "file": "calculator_steps.go",
+	//       "line": 50
+	//     }
+	//   ]
+	// }
+}
+
+func TestDryRunText(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature(calculatorFeature)
+	assert.NoError(t, err)
+
+	dr := steps.NewDryRun(newCalculatorRegistry())
+	dr.AddFeature("calculator.feature", feature)
+	report := dr.Report()
+	assert.False(t, report.OK())
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, report.WriteText(buf))
+	assert.Contains(t, buf.String(), "calculator.feature:19:6: undefined step: Then the result should be \"4\"\n")
+	assert.Contains(t, buf.String(), "calculator.feature:16:7: ambiguous step: And I press the key \"/\"\n")
+	assert.Contains(t, buf.String(), "\tmatches calculator_steps.go:30: ^I press the key \"/\"$\n")
+	assert.Contains(t, buf.String(), "calculator_steps.go:50: the display is blank: unused step definition\n")
+}
+
+func TestDryRunOK(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeature(`
+Feature: Minimal
+  Scenario: Only one
+    Given a Simple Calculator
+`)
+	assert.NoError(t, err)
+
+	r := steps.NewRegistry()
+	r.Define(`a Simple Calculator`, nil)
+	dr := steps.NewDryRun(r)
+	dr.AddFeature("minimal.feature", feature)
+	report := dr.Report()
+	assert.True(t, report.OK())
+	assert.Empty(t, report.Unused)
+}
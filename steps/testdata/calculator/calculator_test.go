@@ -0,0 +1,14 @@
+package calculator
+
+import "github.com/cucumber/godog"
+
+func InitializeScenario(ctx *godog.ScenarioContext) {
+	c := &calculator{}
+	ctx.Step(`^a Simple Calculator$`, c.reset)
+	ctx.Step(`I press the key {string}`, c.press)
+	ctx.Step("the result should be {int}", func(n int) error {
+		return c.check(n)
+	})
+	ctx.Step(pattern, c.reset) // not a literal
+	fmt.Println("a Simple Calculator")
+}
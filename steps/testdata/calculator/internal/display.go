@@ -0,0 +1,6 @@
+package internal
+
+func Register(r Registry) {
+	r.Define(`the display is blank`, displayIsBlank)
+	Step(`the display shows {word}`, displayShows)
+}
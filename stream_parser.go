@@ -0,0 +1,615 @@
+package gherkin
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/muhqu/go-gherkin/events"
+	. "github.com/muhqu/go-gherkin/nodes"
+)
+
+// GherkinStreamParser parses Gherkin read from an io.Reader line by line,
+// emitting the events as soon as they are known. Only the current line, the
+// tags and the description of the current header are held in memory, so huge
+// files, like generated ones with large Examples tables, can be processed
+// while they are being read.
+//
+// The events are the same the GherkinParser emits for the same content, except
+// that "\r\n" line endings are taken for "\n" and that, on error, the events up
+// to the offending line have been emitted already.
+type GherkinStreamParser interface {
+	WithLogFn(LogFn)
+	WithEventProcessor(EventProcessor)
+	// Parse reads and parses the input to its end. Errors of the reader are
+	// returned as they are, syntax errors as *ParseError and failures of an
+	// EventErrorProcessor as *EventError.
+	Parse() error
+}
+
+func NewGherkinStreamParser(r io.Reader) GherkinStreamParser {
+	return &gherkinStreamParser{r: bufio.NewReader(r)}
+}
+
+// ParseGherkinFeatureStream is like ParseGherkinFeature, reading the feature
+// from r.
+func ParseGherkinFeatureStream(r io.Reader) (FeatureNode, error) {
+	b := NewGherkinDOMBuilder()
+	sp := NewGherkinStreamParser(r)
+	sp.WithEventProcessor(b)
+	if err := sp.Parse(); err != nil {
+		return nil, err
+	}
+	return b.Feature(), nil
+}
+
+type streamState int
+
+const (
+	streamBegin          streamState = iota // before the feature
+	streamFeatureHeader                     // in the description of the feature
+	streamBody                              // between scenarios
+	streamScenarioHeader                    // in the description of a background, scenario or outline
+	streamSteps                             // in the steps
+	streamStepArgument                      // after a step, which may be followed by a table or PyString
+	streamPyString                          // in a PyString
+	streamTable                             // after a table row
+	streamExamples                          // after Examples:, which may be followed by a table
+	streamAfterExamples                     // after the examples of an outline
+)
+
+// streamHeader is a Feature, Background, Scenario or Scenario Outline line,
+// held back until its description is complete.
+type streamHeader struct {
+	keyword     string
+	title       string
+	description string
+	tags        []string
+	tagPos      []events.Position
+	pos         events.Position
+	titlePos    events.Position
+}
+
+type gherkinStreamParser struct {
+	r               *bufio.Reader
+	logFn           LogFn
+	eventProcessors []EventProcessor
+
+	line       string // without line ending
+	lineNo     int
+	lineOffset int // byte offset of line
+	nextOffset int
+	eof        bool
+	err        error // of the first EventErrorProcessor that failed
+
+	state    streamState
+	pending  *streamHeader
+	scenario string // keyword of the open background, scenario or outline
+	tableOf  streamState
+	blanks   int // blank lines held back, which belong to a table or PyString that may follow
+	tags     []string
+	tagPos   []events.Position
+}
+
+func (sp *gherkinStreamParser) WithLogFn(logFn LogFn) {
+	sp.logFn = logFn
+}
+
+func (sp *gherkinStreamParser) WithEventProcessor(ep EventProcessor) {
+	sp.eventProcessors = append(sp.eventProcessors, ep)
+}
+
+func (sp *gherkinStreamParser) log(msg string, args ...interface{}) {
+	if sp.logFn != nil {
+		sp.logFn(msg, args...)
+	}
+}
+
+func (sp *gherkinStreamParser) emit(e GherkinEvent) {
+	if sp.err == nil {
+		sp.err = processEvent(sp.eventProcessors, e)
+	}
+}
+
+func (sp *gherkinStreamParser) Parse() error {
+	for !sp.eof {
+		if err := sp.next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// next reads and parses the next line. At the end of the input, eof is set and
+// the end events are emitted.
+func (sp *gherkinStreamParser) next() error {
+	line, err := sp.r.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if line != "" {
+		sp.lineNo++
+		sp.lineOffset = sp.nextOffset
+		sp.nextOffset += len(line)
+		sp.line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		perr := sp.parseLine()
+		if sp.err != nil {
+			return sp.err
+		}
+		if perr != nil {
+			return perr
+		}
+	}
+	if err == io.EOF {
+		sp.eof = true
+		perr := sp.end()
+		if sp.err != nil {
+			return sp.err
+		}
+		return perr
+	}
+	return nil
+}
+
+// position returns the position of the byte at i of the current line.
+func (sp *gherkinStreamParser) position(i int) events.Position {
+	return events.Position{
+		Offset: sp.lineOffset + i,
+		Line:   sp.lineNo,
+		Column: utf8.RuneCountInString(sp.line[:i]) + 1,
+	}
+}
+
+func (sp *gherkinStreamParser) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	return &ParseError{Pos: sp.position(skipWS(sp.line, 0)), Msg: msg}
+}
+
+func (sp *gherkinStreamParser) unexpectedLine() error {
+	return sp.errorf("unexpected %q", trimWS(sp.line))
+}
+
+// ----------------------------------------
+
+func (sp *gherkinStreamParser) parseLine() error {
+	line := sp.line
+	first := skipWS(line, 0)
+	rest := line[first:]
+	blank := rest == ""
+
+	for {
+		switch sp.state {
+		case streamBegin:
+			if blank {
+				return nil
+			}
+			return sp.tagsOrHeader(first, "Feature:")
+
+		case streamFeatureHeader, streamScenarioHeader:
+			if isTagStart(rest) || isScenarioKeyword(rest) ||
+				(sp.state == streamScenarioHeader && stepKeyword(rest) != "") {
+				sp.beginHeader()
+				continue
+			}
+			end, ok := sp.text(first)
+			if !ok {
+				return sp.unexpectedLine()
+			}
+			sp.pending.description += line[first:end] + "\n"
+			return nil
+
+		case streamBody:
+			if blank {
+				sp.blanks++
+				return nil
+			}
+			if isTagStart(rest) || isScenarioKeyword(rest) {
+				sp.blanks = 0
+				return sp.tagsOrHeader(first, "Background:", "Scenario:", "Scenario Outline:")
+			}
+			if len(sp.tags) > 0 {
+				return sp.unexpectedLine()
+			}
+			if rest[0] == '#' {
+				sp.flushBlanks()
+				sp.blankLine(first)
+				return nil
+			}
+			return sp.unexpectedLine()
+
+		case streamSteps:
+			if blank || rest[0] == '#' {
+				sp.blankLine(first)
+				return nil
+			}
+			if keyword := stepKeyword(rest); keyword != "" {
+				return sp.step(first, keyword)
+			}
+			if sp.scenario == "Scenario Outline:" && strings.HasPrefix(rest, "Examples:") {
+				return sp.examples(first)
+			}
+			sp.endScenario()
+			continue
+
+		case streamStepArgument, streamTable, streamExamples:
+			if blank {
+				sp.blanks++
+				return nil
+			}
+			if rest[0] == '|' {
+				sp.blanks = 0
+				return sp.tableRow(first)
+			}
+			if sp.state == streamStepArgument && strings.HasPrefix(rest, `"""`) {
+				sp.blanks = 0
+				return sp.beginPyString(first)
+			}
+			if sp.state == streamTable {
+				sp.log("EndTable")
+				sp.emit(&events.TableEndEvent{})
+				sp.state = sp.tableOf
+			}
+			if sp.state == streamStepArgument {
+				sp.log("EndStep")
+				sp.emit(&events.StepEndEvent{})
+				sp.flushBlanks()
+				sp.state = streamSteps
+			} else {
+				sp.log("EndOutlineExamples")
+				sp.emit(&events.OutlineExamplesEndEvent{})
+				sp.state = streamAfterExamples
+			}
+			continue
+
+		case streamPyString:
+			if !strings.HasPrefix(rest, `"""`) {
+				sp.log("BufferPyString: %#v", line)
+				sp.emit(&events.PyStringLineEvent{Line: line, Pos: sp.position(0)})
+				return nil
+			}
+			if !sp.lineEnd(first + 3) {
+				return sp.unexpectedLine()
+			}
+			sp.log("EndPyString")
+			sp.emit(&events.PyStringEndEvent{})
+			sp.log("EndStep")
+			sp.emit(&events.StepEndEvent{})
+			sp.state = streamSteps
+			return nil
+
+		case streamAfterExamples:
+			if blank {
+				sp.blanks++
+				return nil
+			}
+			if strings.HasPrefix(rest, "Examples:") {
+				sp.blanks = 0
+				return sp.examples(first)
+			}
+			sp.flushBlanks()
+			if rest[0] == '#' {
+				sp.blankLine(first)
+				return nil
+			}
+			sp.endScenario()
+			continue
+		}
+	}
+}
+
+// end emits the end events of what is open at the end of the input.
+func (sp *gherkinStreamParser) end() error {
+	switch sp.state {
+	case streamBegin:
+		if len(sp.tags) > 0 {
+			return sp.errorf("unexpected end of input")
+		}
+		return nil
+	case streamPyString:
+		return sp.errorf("unexpected end of input")
+	case streamBody:
+		if len(sp.tags) > 0 {
+			return sp.errorf("unexpected end of input")
+		}
+	case streamFeatureHeader, streamScenarioHeader:
+		sp.beginHeader()
+	}
+	switch sp.state {
+	case streamTable:
+		sp.log("EndTable")
+		sp.emit(&events.TableEndEvent{})
+		sp.state = sp.tableOf
+	}
+	switch sp.state {
+	case streamStepArgument:
+		sp.log("EndStep")
+		sp.emit(&events.StepEndEvent{})
+	case streamExamples:
+		sp.log("EndOutlineExamples")
+		sp.emit(&events.OutlineExamplesEndEvent{})
+	}
+	sp.flushBlanks()
+	if sp.state != streamBody {
+		sp.endScenario()
+	}
+	sp.log("EndFeature")
+	sp.emit(&events.FeatureEndEvent{})
+	return nil
+}
+
+// ----------------------------------------
+
+// tagsOrHeader handles a line of tags, which may be followed by one of the
+// keywords on the same line.
+func (sp *gherkinStreamParser) tagsOrHeader(i int, keywords ...string) error {
+	line := sp.line
+	tagged := false
+	for i < len(line) && isTagStart(line[i:]) {
+		end := i + 1
+		for end < len(line) && isWordChar(line[end]) {
+			end++
+		}
+		sp.tags = append(sp.tags, line[i+1:end])
+		sp.tagPos = append(sp.tagPos, sp.position(i))
+		i = skipWS(line, end)
+		tagged = true
+	}
+	if i == len(line) {
+		return nil
+	}
+	if tagged && line[i] == '#' {
+		sp.comment(i)
+		return nil
+	}
+	for _, keyword := range keywords {
+		if strings.HasPrefix(line[i:], keyword) {
+			return sp.header(i, keyword)
+		}
+	}
+	return sp.unexpectedLine()
+}
+
+// header handles the line of a Feature, Background, Scenario or Scenario
+// Outline keyword at i.
+func (sp *gherkinStreamParser) header(i int, keyword string) error {
+	start := skipWS(sp.line, i+len(keyword))
+	end, ok := sp.text(start)
+	if !ok {
+		return sp.unexpectedLine()
+	}
+	sp.pending = &streamHeader{
+		keyword:  keyword,
+		title:    trimWS(sp.line[start:end]),
+		tags:     sp.tags,
+		tagPos:   sp.tagPos,
+		pos:      sp.position(i),
+		titlePos: sp.position(start),
+	}
+	sp.tags, sp.tagPos = nil, nil
+	if keyword == "Feature:" {
+		sp.state = streamFeatureHeader
+	} else {
+		sp.state = streamScenarioHeader
+	}
+	return nil
+}
+
+// beginHeader emits the event of the header, now that its description is
+// complete.
+func (sp *gherkinStreamParser) beginHeader() {
+	h := sp.pending
+	description := trimWSML(h.description)
+	switch h.keyword {
+	case "Feature:":
+		sp.log("BeginFeature: %#v: %#v tags:%+v", h.title, description, h.tags)
+		sp.emit(&events.FeatureEvent{Title: h.title, Description: description, Tags: h.tags, TagPositions: h.tagPos, Pos: h.pos})
+		sp.state = streamBody
+	case "Background:":
+		sp.log("BeginBackground: %#v: %#v tags:%+v", h.title, description, h.tags)
+		sp.emit(&events.BackgroundEvent{Title: h.title, Description: description, Tags: h.tags, TagPositions: h.tagPos, Pos: h.pos})
+		sp.state = streamSteps
+	case "Scenario:":
+		sp.log("BeginScenario: %#v: %#v tags:%+v", h.title, description, h.tags)
+		sp.emit(&events.ScenarioEvent{Title: h.title, Description: description, Tags: h.tags, TagPositions: h.tagPos, Pos: h.pos})
+		sp.state = streamSteps
+	case "Scenario Outline:":
+		sp.log("BeginOutline: %#v: %#v tags:%+v", h.title, description, h.tags)
+		sp.emit(&events.OutlineEvent{Title: h.title, Description: description, Tags: h.tags, TagPositions: h.tagPos, Pos: h.pos, TitlePos: h.titlePos})
+		sp.state = streamSteps
+	}
+	if h.keyword != "Feature:" {
+		sp.scenario = h.keyword
+	}
+	sp.pending = nil
+}
+
+func (sp *gherkinStreamParser) endScenario() {
+	switch sp.scenario {
+	case "Background:":
+		sp.log("EndBackground")
+		sp.emit(&events.BackgroundEndEvent{})
+	case "Scenario:":
+		sp.log("EndScenario")
+		sp.emit(&events.ScenarioEndEvent{})
+	case "Scenario Outline:":
+		sp.log("EndOutline")
+		sp.emit(&events.OutlineEndEvent{})
+	}
+	sp.scenario = ""
+	sp.state = streamBody
+}
+
+func (sp *gherkinStreamParser) step(i int, keyword string) error {
+	start := skipWS(sp.line, i+len(keyword))
+	end, ok := sp.text(start)
+	if !ok || end == start {
+		return sp.unexpectedLine()
+	}
+	text := trimWS(sp.line[start:end])
+	sp.log("BeginStep: %#v: %#v", keyword, text)
+	sp.emit(&events.StepEvent{StepType: keyword, Text: text, Pos: sp.position(i), TextPos: sp.position(start)})
+	sp.state = streamStepArgument
+	return nil
+}
+
+func (sp *gherkinStreamParser) examples(i int) error {
+	start := skipWS(sp.line, i+len("Examples:"))
+	end, ok := sp.text(start)
+	if !ok {
+		return sp.unexpectedLine()
+	}
+	sp.log("BeginOutlineExamples")
+	sp.emit(&events.OutlineExamplesEvent{Title: trimWS(sp.line[start:end]), Pos: sp.position(i)})
+	sp.state = streamExamples
+	return nil
+}
+
+func (sp *gherkinStreamParser) beginPyString(i int) error {
+	mediaType := trimWS(sp.line[i+3:])
+	sp.log("BeginPyString: indent=%d mediaType=%q", i, mediaType)
+	sp.emit(&events.PyStringEvent{Intent: sp.line[:i], MediaType: mediaType, Pos: sp.position(i)})
+	sp.state = streamPyString
+	return nil
+}
+
+// tableRow handles the table row whose leading pipe is at i.
+func (sp *gherkinStreamParser) tableRow(i int) error {
+	line := sp.line
+	var cells []int // offsets of cells followed by a pipe
+	end := i + 1
+	for {
+		n := strings.IndexByte(line[end:], '|')
+		if n <= 0 {
+			break
+		}
+		cells = append(cells, end)
+		end += n + 1
+	}
+	if len(cells) == 0 {
+		return sp.unexpectedLine()
+	}
+	if rest := skipWS(line, end); rest < len(line) && line[rest] != '#' {
+		return sp.unexpectedLine()
+	}
+
+	if sp.state != streamTable {
+		sp.log("BeginTable")
+		sp.emit(&events.TableEvent{Pos: sp.position(i)})
+		sp.tableOf = sp.state
+		sp.state = streamTable
+	}
+	sp.log("BeginTableRow")
+	sp.emit(&events.TableRowEvent{Pos: sp.position(i)})
+	for n, start := range cells {
+		cellEnd := end - 1
+		if n+1 < len(cells) {
+			cellEnd = cells[n+1] - 1
+		}
+		content := trimWS(line[start:cellEnd])
+		sp.log("EndTableCell: %#v", content)
+		sp.emit(&events.TableCellEvent{Content: content, Pos: sp.position(skipWS(line, start))})
+	}
+	sp.lineEnd(end)
+	sp.log("EndTableRow")
+	sp.emit(&events.TableRowEndEvent{})
+	return nil
+}
+
+// text returns the end of the text starting at i, which is where a comment
+// starts or the end of the line. The comment is emitted. It is not ok if the
+// text has an unbalanced quote.
+func (sp *gherkinStreamParser) text(i int) (end int, ok bool) {
+	line := sp.line
+	for end = i; end < len(line); end++ {
+		switch line[end] {
+		case '\\':
+			end++
+		case '"':
+			n := closingQuote(line[end+1:])
+			if n < 0 {
+				return end, false
+			}
+			end += n + 1
+		case '#':
+			sp.comment(end)
+			return end, true
+		}
+	}
+	return len(line), true
+}
+
+// closingQuote returns the index of the first unescaped '"' of s, or -1.
+func closingQuote(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// lineEnd reports whether the line is blank from i on, but for a comment,
+// which is emitted.
+func (sp *gherkinStreamParser) lineEnd(i int) bool {
+	i = skipWS(sp.line, i)
+	if i == len(sp.line) {
+		return true
+	}
+	if sp.line[i] == '#' {
+		sp.comment(i)
+		return true
+	}
+	return false
+}
+
+func (sp *gherkinStreamParser) comment(i int) {
+	sp.log("triggerComment")
+	sp.emit(&events.CommentEvent{Comment: sp.line[i+1:], Pos: sp.position(i)})
+}
+
+// blankLine handles a line that is blank but for a comment starting at i.
+func (sp *gherkinStreamParser) blankLine(i int) {
+	if i < len(sp.line) {
+		sp.comment(i)
+	}
+	sp.log("triggerBlankLine")
+	sp.emit(&events.BlankLineEvent{})
+}
+
+func (sp *gherkinStreamParser) flushBlanks() {
+	for ; sp.blanks > 0; sp.blanks-- {
+		sp.log("triggerBlankLine")
+		sp.emit(&events.BlankLineEvent{})
+	}
+}
+
+// ----------------------------------------
+
+func isTagStart(s string) bool {
+	return len(s) > 1 && s[0] == '@' && isWordChar(s[1])
+}
+
+func isWordChar(c byte) bool {
+	return !strings.ContainsRune("\r\n\t \"#", rune(c))
+}
+
+func isScenarioKeyword(s string) bool {
+	return strings.HasPrefix(s, "Background:") || strings.HasPrefix(s, "Scenario:") || strings.HasPrefix(s, "Scenario Outline:")
+}
+
+var stepKeywords = []string{"Given", "When", "Then", "And", "Or", "But", "*"}
+
+// stepKeyword returns the step keyword s starts with, "" if none.
+func stepKeyword(s string) string {
+	for _, keyword := range stepKeywords {
+		if strings.HasPrefix(s, keyword) {
+			return keyword
+		}
+	}
+	return ""
+}
@@ -0,0 +1,196 @@
+package gherkin_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/muhqu/go-gherkin"
+	"github.com/muhqu/go-gherkin/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func recordEvents(add func(gherkin.EventProcessor)) *[]gherkin.GherkinEvent {
+	var recorded []gherkin.GherkinEvent
+	add(gherkin.EventProcessorFn(func(e gherkin.GherkinEvent) {
+		recorded = append(recorded, e)
+	}))
+	return &recorded
+}
+
+// streamEquivalenceCases are parsed by both parsers, which must emit the same
+// events.
+var streamEquivalenceCases = map[string]string{
+	"empty":       "",
+	"blank":       "\n  \n",
+	"no newline":  "Feature: F\n  Scenario: S\n    Given a step",
+	"tags inline": "@a @b Feature: F\n@c\n\n@d # tagged\n  Scenario: S # c\n    * a step\n",
+	"descriptions": `Feature: F
+  Given is no step here
+
+  # neither is a comment
+  Bla "quoted" \# escaped
+Background: B
+  about the background
+
+  Given a step
+`,
+	"blank lines before arguments": `Feature: F
+  Scenario: S
+    Given a table
+
+      | a | b |
+
+      | c | d | # row comment
+
+    And a PyString
+
+      """
+      line 1
+
+        line 3
+      """ # closing comment
+
+    Then a step
+    # comment
+
+`,
+	"blank lines around examples": `Feature: F
+  Scenario Outline: O <x>
+
+    Given <x>
+
+    Examples:
+
+      | x |
+      | 1 |
+
+
+    Examples: second
+    Examples: third
+
+      | x |
+      | 2 |
+
+  # trailing comment
+  @t
+  Scenario: Last
+    When something happens
+
+    Then nothing else
+`,
+	"outline description": `Feature: F
+  Scenario Outline: O
+    Examples:
+    | x |
+`,
+	"odd tables": "Feature: F\n  Scenario: S\n    Given x\n      |   | a|b|\n      | # c |\n",
+}
+
+func TestStreamParserEmitsSameEvents(t *testing.T) {
+	cases := map[string]string{
+		"benchmark":  benchmarkGherkinText,
+		"calculator": calculatorGherkin,
+	}
+	for name, content := range streamEquivalenceCases {
+		cases[name] = content
+	}
+	files, _ := filepath.Glob("formater/testdata/*.feature")
+	more, _ := filepath.Glob("cmd/gherkin/testdata/features/*/*.feature")
+	for _, file := range append(files, more...) {
+		data, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+		cases[file] = string(data)
+	}
+
+	for name, content := range cases {
+		gp := gherkin.NewGherkinParser(content)
+		expected := recordEvents(gp.WithEventProcessor)
+		gp.Init()
+		if !assert.NoError(t, gp.Parse(), name) {
+			continue
+		}
+		gp.Execute()
+
+		sp := gherkin.NewGherkinStreamParser(iotest.OneByteReader(strings.NewReader(content)))
+		actual := recordEvents(sp.WithEventProcessor)
+		assert.NoError(t, sp.Parse(), name)
+		assert.Equal(t, *expected, *actual, name)
+	}
+}
+
+func TestStreamParserErrors(t *testing.T) {
+	for content, msg := range map[string]string{
+		"# comment\nFeature: F\n":     `1:1: unexpected "# comment"`,
+		"@a\n# c\nFeature: F\n":       `2:1: unexpected "# c"`,
+		"@a\n":                        "1:1: unexpected end of input",
+		"Feature: F\n  Bad \"quote\n": `2:3: unexpected "Bad \"quote"`,
+		"Feature: F\n  Scenario: S\n    Given x\n  | a\n":       `4:3: unexpected "| a"`,
+		"Feature: F\n  Scenario: S\n    Given x\n  \"\"\"\n":    "4:3: unexpected end of input",
+		"Feature: F\n  Scenario: S\n    Given x\n  Examples:\n": `4:3: unexpected "Examples:"`,
+	} {
+		gp := gherkin.NewGherkinParser(content)
+		gp.Init()
+		assert.Error(t, gp.Parse(), content)
+
+		err := gherkin.NewGherkinStreamParser(strings.NewReader(content)).Parse()
+		if e, ok := err.(*gherkin.ParseError); assert.True(t, ok, content) {
+			assert.Equal(t, msg, e.Pos.String()+": "+e.Msg, content)
+		}
+	}
+}
+
+func TestStreamParserCRLF(t *testing.T) {
+	feature, err := gherkin.ParseGherkinFeatureStream(strings.NewReader("Feature: F\r\n  Scenario: S\r\n    Given a\r\n      | x |\r\n"))
+	if assert.NoError(t, err) {
+		step := feature.Scenarios()[0].Steps()[0]
+		assert.Equal(t, "a", step.Text())
+		assert.Equal(t, [][]string{{"x"}}, step.Table().Rows())
+		assert.Equal(t, 46, step.Table().Position().Offset)
+	}
+}
+
+// The events of a table are emitted while its rows are read.
+func TestStreamParserIsIncremental(t *testing.T) {
+	r, w := io.Pipe()
+	sp := gherkin.NewGherkinStreamParser(r)
+	cells := make(chan string)
+	sp.WithEventProcessor(gherkin.EventProcessorFn(func(e gherkin.GherkinEvent) {
+		if cell, ok := e.(*events.TableCellEvent); ok {
+			cells <- cell.Content
+		}
+	}))
+	done := make(chan error)
+	go func() { done <- sp.Parse() }()
+
+	w.Write([]byte("Feature: F\n  Scenario Outline: O\n    Given <x>\n    Examples:\n      | x |\n"))
+	assert.Equal(t, "x", <-cells)
+	for _, row := range []string{"1", "2", "3"} {
+		w.Write([]byte("      | " + row + " |\n"))
+		assert.Equal(t, row, <-cells)
+	}
+	w.Close()
+	assert.NoError(t, <-done)
+}
+
+func TestParseGherkinFeatureStream(t *testing.T) {
+	expected, err := gherkin.ParseGherkinFeature(benchmarkGherkinText)
+	assert.NoError(t, err)
+	actual, err := gherkin.ParseGherkinFeatureStream(bytes.NewBufferString(benchmarkGherkinText))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	feature, err := gherkin.ParseGherkinFeatureStream(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Nil(t, feature)
+}
+
+func Benchmark_ParseGherkinFeatureStream(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gherkin.ParseGherkinFeatureStream(strings.NewReader(benchmarkGherkinText))
+	}
+}